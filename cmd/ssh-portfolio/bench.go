@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// benchKeys are what a bench session presses while it browses.
+var benchKeys = []string{"1", "2", "3", "4", "h", "l", "j", "k"}
+
+// Terminal queries the server sends, in order, and what a dark-background
+// xterm would answer. Without an answer the session waits out the query
+// timeout before drawing anything.
+var benchReplies = []struct{ query, reply string }{
+	{"\x1b]11;?", "\x1b]11;rgb:0000/0000/0000\x07"}, // background color
+	{"\x1b[c", "\x1b[?62;22c"},                      // primary device attributes
+}
+
+const altScreen = "\x1b[?1049h"
+
+// benchSession is what one simulated visitor saw.
+type benchSession struct {
+	Handshake  time.Duration `json:"handshake_ns"`
+	FirstFrame time.Duration `json:"first_frame_ns"` // from opening the shell
+	Bytes      int64         `json:"bytes"`
+	Err        string        `json:"error,omitempty"`
+}
+
+// benchServer is the server's own view, scraped from its /metrics.
+type benchServer struct {
+	CPUSeconds    float64 `json:"cpu_seconds"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	PeakRSS       float64 `json:"peak_rss_bytes"`
+	PeakGoroutine float64 `json:"peak_goroutines"`
+}
+
+type benchStats struct {
+	P50 time.Duration `json:"p50_ns"`
+	P95 time.Duration `json:"p95_ns"`
+	P99 time.Duration `json:"p99_ns"`
+	Max time.Duration `json:"max_ns"`
+}
+
+type benchReport struct {
+	Addr       string         `json:"addr"`
+	Sessions   int            `json:"sessions"`
+	Failed     int            `json:"failed"`
+	Wall       time.Duration  `json:"wall_ns"`
+	Handshake  benchStats     `json:"handshake"`
+	FirstFrame benchStats     `json:"first_frame"`
+	AvgBytes   int64          `json:"avg_bytes"`
+	MaxBytes   int64          `json:"max_bytes"`
+	Server     *benchServer   `json:"server,omitempty"`
+	Results    []benchSession `json:"results"`
+}
+
+// runBench implements `ssh-portfolio bench`: open many concurrent PTY
+// sessions against a running server and report how it held up.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:22", "server to load")
+	sessions := fs.Int("sessions", 50, "concurrent sessions to open")
+	ramp := fs.Duration("ramp", 5*time.Second, "spread session starts over this long")
+	hold := fs.Duration("hold", 20*time.Second, "how long each session browses before quitting")
+	think := fs.Duration("think", 500*time.Millisecond, "average pause between keypresses")
+	user := fs.String("user", "bench", "ssh username")
+	metricsURL := fs.String("metrics", "", "the server's /metrics URL, for its CPU and memory (empty = skip)")
+	jsonOut := fs.String("json", "", `also write the report as JSON to this file ("-" = stdout, instead of the table)`)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ssh-portfolio bench [flags]")
+		fmt.Fprint(fs.Output(), "\nRun the server with -max-sessions-per-ip 0 -conn-rate 0, or most sessions will be turned away.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var scraper *benchScraper
+	if *metricsURL != "" {
+		var err error
+		if scraper, err = startScraper(*metricsURL); err != nil {
+			log.Fatalf("failed to read server metrics: %v", err)
+		}
+	}
+
+	start := time.Now()
+	results := make([]benchSession, *sessions)
+	var wg sync.WaitGroup
+	for i := range *sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if *sessions > 1 {
+				time.Sleep(*ramp * time.Duration(i) / time.Duration(*sessions-1))
+			}
+			results[i] = benchOne(*addr, *user, *hold, *think)
+		}()
+	}
+	wg.Wait()
+
+	report := summarizeBench(*addr, results, time.Since(start))
+	if scraper != nil {
+		server, err := scraper.stop()
+		if err != nil {
+			log.Printf("failed to read server metrics: %v", err)
+		} else {
+			server.CPUPercent = 100 * server.CPUSeconds / report.Wall.Seconds()
+			report.Server = &server
+		}
+	}
+
+	if *jsonOut != "" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("failed to encode report: %v", err)
+		}
+		b = append(b, '\n')
+		if *jsonOut == "-" {
+			os.Stdout.Write(b)
+			return
+		}
+		if err := os.WriteFile(*jsonOut, b, 0o644); err != nil {
+			log.Fatalf("failed to write report: %v", err)
+		}
+	}
+	printBench(os.Stdout, report)
+}
+
+// benchOne plays one visitor: connect, skip the intro, press keys for a
+// while, quit.
+func benchOne(addr, user string, hold, think time.Duration) benchSession {
+	var res benchSession
+	fail := func(err error) benchSession {
+		res.Err = err.Error()
+		return res
+	}
+
+	begin := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return fail(err)
+	}
+	c, chans, reqs, err := gossh.NewClientConn(conn, addr, &gossh.ClientConfig{
+		User: user,
+		// the server takes any login; keyboard-interactive with no
+		// prompts is the cheapest, with no key to sign with
+		Auth: []gossh.AuthMethod{gossh.KeyboardInteractive(
+			func(string, string, []string, []bool) ([]string, error) { return nil, nil },
+		)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(), // load testing our own server
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		conn.Close()
+		return fail(err)
+	}
+	res.Handshake = time.Since(begin)
+	client := gossh.NewClient(c, chans, reqs)
+	defer client.Close()
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return fail(err)
+	}
+	defer sess.Close()
+
+	if err := sess.RequestPty("xterm-256color", 40, 120, gossh.TerminalModes{}); err != nil {
+		return fail(err)
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return fail(err)
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return fail(err)
+	}
+
+	shellAt := time.Now()
+	if err := sess.Shell(); err != nil {
+		return fail(err)
+	}
+
+	type screen struct {
+		bytes int64
+		first time.Duration
+	}
+	firstFrame := make(chan struct{})
+	done := make(chan screen, 1)
+	go func() {
+		n, first := readScreen(stdout, stdin, shellAt, firstFrame)
+		done <- screen{n, first}
+	}()
+
+	select {
+	case <-firstFrame:
+	case <-done:
+		return fail(fmt.Errorf("session closed before the first frame"))
+	case <-time.After(30 * time.Second):
+		return fail(fmt.Errorf("no frame after 30s"))
+	}
+
+	r := rand.New(rand.NewPCG(uint64(begin.UnixNano()), 0))
+	io.WriteString(stdin, " ") // skip the intro
+	for until := time.Now().Add(hold); time.Now().Before(until); {
+		time.Sleep(think/2 + time.Duration(r.Int64N(int64(think)+1)))
+		if _, err := io.WriteString(stdin, benchKeys[r.IntN(len(benchKeys))]); err != nil {
+			return fail(err)
+		}
+	}
+	// Keys that arrive together read as one pasted string, so pause first.
+	// esc puts down any prompt the session opened, so the q isn't typed
+	// into it; on its own it quits too.
+	time.Sleep(think)
+	io.WriteString(stdin, "\x1b")
+	time.Sleep(think)
+	io.WriteString(stdin, "q")
+
+	select {
+	case sc := <-done:
+		res.Bytes, res.FirstFrame = sc.bytes, sc.first
+	case <-time.After(10 * time.Second):
+		return fail(fmt.Errorf("session did not close after quitting"))
+	}
+	return res
+}
+
+// readScreen drains the session, answering terminal queries, and notes
+// when the first frame arrived: the first output after the program
+// switched to the alternate screen.
+func readScreen(r io.Reader, w io.Writer, since time.Time, firstFrame chan<- struct{}) (int64, time.Duration) {
+	var total int64
+	var first time.Duration
+	var seen []byte // output before the first frame, to spot the alt screen and queries in
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		total += int64(n)
+		if n > 0 && first == 0 {
+			seen = append(seen, buf[:n]...)
+			for _, q := range benchReplies {
+				if bytes.Contains(seen, []byte(q.query)) {
+					io.WriteString(w, q.reply)
+					seen = bytes.ReplaceAll(seen, []byte(q.query), nil)
+				}
+			}
+			if i := bytes.Index(seen, []byte(altScreen)); i >= 0 && len(seen) > i+len(altScreen) {
+				first = time.Since(since)
+				seen = nil
+				close(firstFrame)
+			}
+		}
+		if err != nil {
+			return total, first
+		}
+	}
+}
+
+func summarizeBench(addr string, results []benchSession, wall time.Duration) benchReport {
+	report := benchReport{Addr: addr, Sessions: len(results), Wall: wall, Results: results}
+
+	var handshakes, frames []time.Duration
+	var bytesTotal int64
+	for _, res := range results {
+		if res.Err != "" {
+			report.Failed++
+			continue
+		}
+		handshakes = append(handshakes, res.Handshake)
+		frames = append(frames, res.FirstFrame)
+		bytesTotal += res.Bytes
+		report.MaxBytes = max(report.MaxBytes, res.Bytes)
+	}
+	if ok := len(results) - report.Failed; ok > 0 {
+		report.AvgBytes = bytesTotal / int64(ok)
+	}
+	report.Handshake = percentiles(handshakes)
+	report.FirstFrame = percentiles(frames)
+	return report
+}
+
+func percentiles(ds []time.Duration) benchStats {
+	if len(ds) == 0 {
+		return benchStats{}
+	}
+	slices.Sort(ds)
+	at := func(p float64) time.Duration {
+		return ds[min(int(p*float64(len(ds))), len(ds)-1)]
+	}
+	return benchStats{P50: at(0.50), P95: at(0.95), P99: at(0.99), Max: ds[len(ds)-1]}
+}
+
+func printBench(out io.Writer, r benchReport) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	ms := func(d time.Duration) string { return d.Round(100 * time.Microsecond).String() }
+
+	fmt.Fprintf(w, "Server\t%s\n", r.Addr)
+	fmt.Fprintf(w, "Sessions\t%d (%d failed)\n", r.Sessions, r.Failed)
+	fmt.Fprintf(w, "Wall time\t%s\n", r.Wall.Round(time.Millisecond))
+
+	fmt.Fprintln(w, "\n\tp50\tp95\tp99\tmax")
+	fmt.Fprintf(w, "Handshake\t%s\t%s\t%s\t%s\n", ms(r.Handshake.P50), ms(r.Handshake.P95), ms(r.Handshake.P99), ms(r.Handshake.Max))
+	fmt.Fprintf(w, "First frame\t%s\t%s\t%s\t%s\n", ms(r.FirstFrame.P50), ms(r.FirstFrame.P95), ms(r.FirstFrame.P99), ms(r.FirstFrame.Max))
+
+	fmt.Fprintf(w, "\nBytes per session\t%d avg, %d max\n", r.AvgBytes, r.MaxBytes)
+	if s := r.Server; s != nil {
+		fmt.Fprintf(w, "Server CPU\t%.2fs (%.0f%% of one core)\n", s.CPUSeconds, s.CPUPercent)
+		fmt.Fprintf(w, "Server peak RSS\t%.1f MiB\n", s.PeakRSS/(1<<20))
+		fmt.Fprintf(w, "Server peak goroutines\t%.0f\n", s.PeakGoroutine)
+	}
+
+	errs := map[string]int{}
+	for _, res := range r.Results {
+		if res.Err != "" {
+			errs[res.Err]++
+		}
+	}
+	if len(errs) > 0 {
+		fmt.Fprintln(w, "\nError\tSessions")
+		for msg, n := range errs {
+			fmt.Fprintf(w, "%s\t%d\n", msg, n)
+		}
+	}
+	w.Flush()
+}
+
+// benchScraper polls the server's /metrics during the run for its peaks,
+// and diffs CPU time across it.
+type benchScraper struct {
+	url   string
+	first map[string]float64
+	peak  benchServer
+	quit  chan struct{}
+	done  chan struct{}
+}
+
+func startScraper(url string) (*benchScraper, error) {
+	first, err := scrapeMetrics(url)
+	if err != nil {
+		return nil, err
+	}
+	s := &benchScraper{url: url, first: first, quit: make(chan struct{}), done: make(chan struct{})}
+	s.observe(first)
+
+	go func() {
+		defer close(s.done)
+		t := time.NewTicker(time.Second)
+		defer t.Stop()
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-t.C:
+				if m, err := scrapeMetrics(url); err == nil {
+					s.observe(m)
+				}
+			}
+		}
+	}()
+	return s, nil
+}
+
+func (s *benchScraper) observe(m map[string]float64) {
+	s.peak.PeakRSS = max(s.peak.PeakRSS, m["process_resident_memory_bytes"])
+	s.peak.PeakGoroutine = max(s.peak.PeakGoroutine, m["go_goroutines"])
+}
+
+func (s *benchScraper) stop() (benchServer, error) {
+	close(s.quit)
+	<-s.done
+
+	last, err := scrapeMetrics(s.url)
+	if err != nil {
+		return benchServer{}, err
+	}
+	s.observe(last)
+	s.peak.CPUSeconds = last["process_cpu_seconds_total"] - s.first["process_cpu_seconds_total"]
+	return s.peak, nil
+}
+
+// scrapeMetrics reads the unlabelled samples from a Prometheus text
+// exposition, which is all the bench needs.
+func scrapeMetrics(url string) (map[string]float64, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	out := map[string]float64{}
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") || strings.Contains(line, "{") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fields[1], 64); err == nil {
+			out[fields[0]] = v
+		}
+	}
+	return out, sc.Err()
+}
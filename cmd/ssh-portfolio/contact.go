@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+)
+
+type contactFlags struct {
+	smtp, smtpUser, from, to string
+	webhook                  string
+	maildir                  string
+	spool                    string
+	perSender                int
+	window                   time.Duration
+	maxSpool                 int
+}
+
+// openContactQueue sets up delivery for the contact form from whichever
+// notifier flag was given, and starts the queue. With none it returns nil
+// and the form stays hidden.
+func openContactQueue(f contactFlags, p *portfolio.Snapshot) *contact.Queue {
+	var notifiers []contact.Notifier
+	if f.smtp != "" {
+		to := f.to
+		if to == "" {
+			to = p.Contact.Email
+		}
+		if f.from == "" || to == "" {
+			log.Fatalf("-contact-smtp needs -contact-from and -contact-to (or contact.email in the portfolio)")
+		}
+		notifiers = append(notifiers, contact.SMTP{
+			Addr:     f.smtp,
+			From:     f.from,
+			To:       to,
+			Username: f.smtpUser,
+			Password: os.Getenv("CONTACT_SMTP_PASSWORD"),
+		})
+	}
+	if f.webhook != "" {
+		notifiers = append(notifiers, contact.Webhook{URL: f.webhook})
+	}
+	if f.maildir != "" {
+		notifiers = append(notifiers, contact.Maildir{Dir: f.maildir})
+	}
+
+	switch len(notifiers) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		log.Fatalf("pick one of -contact-smtp, -contact-webhook and -contact-maildir")
+	}
+
+	q, err := contact.NewQueue(f.spool, notifiers[0], contact.Options{
+		PerSender: f.perSender,
+		Window:    f.window,
+		MaxSpool:  f.maxSpool,
+	})
+	if err != nil {
+		log.Fatalf("failed to open contact spool: %v", err)
+	}
+	go q.Run(nil) // for the life of the process
+	return q
+}
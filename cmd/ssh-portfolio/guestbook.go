@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+)
+
+// runGuestbook implements `ssh-portfolio guestbook`: moderate the guestbook
+// of a running (or stopped) server.
+func runGuestbook(args []string) {
+	fs := flag.NewFlagSet("guestbook", flag.ExitOnError)
+	path := fs.String("guestbook", "guestbook.jsonl", "guestbook log written by the server")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ssh-portfolio guestbook [flags] pending|list|approve ID...|delete ID...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	book, err := guestbook.Open(*path, guestbook.Options{})
+	if err != nil {
+		log.Fatalf("failed to open guestbook: %v", err)
+	}
+	defer book.Close()
+
+	cmd, ids := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "pending":
+		printEntries(book.Pending())
+	case "list":
+		printEntries(book.All())
+	case "approve", "delete":
+		if len(ids) == 0 {
+			log.Fatalf("%s needs at least one entry ID", cmd)
+		}
+		moderate := book.Approve
+		if cmd == "delete" {
+			moderate = book.Delete
+		}
+		for _, id := range ids {
+			if err := moderate(id, time.Now()); err != nil {
+				log.Fatalf("failed to %s %s: %v", cmd, id, err)
+			}
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+func printEntries(entries []guestbook.Entry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTime\tStatus\tUser\tKey\tMessage")
+	for _, e := range entries {
+		status := "pending"
+		if e.Approved {
+			status = "approved"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.ID, e.Time.Format(time.DateTime), status, e.User, e.Fingerprint, e.Message)
+	}
+	w.Flush()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	sshserver "github.com/Shbhom/ssh-portfolio/internal/ssh-server"
+	"github.com/charmbracelet/keygen"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshfpAlgorithms maps key types to their SSHFP algorithm numbers (RFC 4255,
+// 6594, 7479).
+var sshfpAlgorithms = map[string]int{
+	gossh.KeyAlgoRSA:      1,
+	gossh.KeyAlgoECDSA256: 3,
+	gossh.KeyAlgoECDSA384: 3,
+	gossh.KeyAlgoECDSA521: 3,
+	gossh.KeyAlgoED25519:  4,
+}
+
+// runKeygen implements `ssh-portfolio keygen`: make sure the host keys exist
+// and print what to publish for them.
+func runKeygen(args []string) {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	dir := fs.String("host-key-dir", ".", "directory holding the host keys")
+	host := fs.String("host", "localhost", "hostname visitors connect to, for known_hosts and SSHFP lines")
+	port := fs.Int("port", 22, "port visitors connect to, for known_hosts lines")
+	rotate := fs.Bool("rotate", false, "stage a fresh set of keys to publish alongside the current ones")
+	promote := fs.Bool("promote", false, "replace the current keys with the staged ones")
+	fs.Parse(args)
+
+	if *promote {
+		if err := sshserver.PromoteHostKeys(*dir); err != nil {
+			log.Fatalf("failed to promote host keys: %v", err)
+		}
+		fmt.Println("Staged keys are now current; restart the server to serve them.")
+	}
+
+	current, err := sshserver.LoadHostKeys(*dir)
+	if err != nil {
+		log.Fatalf("failed to load host keys: %v", err)
+	}
+
+	var staged []*keygen.KeyPair
+	if *rotate {
+		staged, err = sshserver.RotateHostKeys(*dir)
+	} else {
+		staged, err = sshserver.StagedHostKeys(*dir)
+	}
+	if err != nil {
+		log.Fatalf("failed to load staged host keys: %v", err)
+	}
+
+	printKeys("Current host keys", current, *host, *port)
+	if staged != nil {
+		fmt.Println()
+		printKeys("Staged host keys (publish these too, then run keygen -promote)", staged, *host, *port)
+	}
+}
+
+func printKeys(title string, keys []*keygen.KeyPair, host string, port int) {
+	fmt.Println(title + ":")
+	for _, k := range keys {
+		pk := k.PublicKey()
+		fmt.Printf("  %-20s %s\n", pk.Type(), gossh.FingerprintSHA256(pk))
+	}
+
+	addr := knownhosts.Normalize(net.JoinHostPort(host, strconv.Itoa(port)))
+	fmt.Println("\n  known_hosts:")
+	for _, k := range keys {
+		fmt.Println("    " + knownhosts.Line([]string{addr}, k.PublicKey()))
+	}
+
+	fmt.Println("\n  SSHFP:")
+	for _, k := range keys {
+		pk := k.PublicKey()
+		sum := sha256.Sum256(pk.Marshal())
+		fmt.Printf("    %s. IN SSHFP %d 2 %s\n",
+			strings.TrimSuffix(host, "."), sshfpAlgorithms[pk.Type()], hex.EncodeToString(sum[:]))
+	}
+}
@@ -4,26 +4,222 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/Shbhom/ssh-portfolio/internal/analytics"
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	"github.com/Shbhom/ssh-portfolio/internal/recording"
 	sshserver "github.com/Shbhom/ssh-portfolio/internal/ssh-server"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "stats":
+			runStats(os.Args[2:])
+			return
+		case "keygen":
+			runKeygen(os.Args[2:])
+			return
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "guestbook":
+			runGuestbook(os.Args[2:])
+			return
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		}
+	}
+
 	port := flag.Int("port", 22, "port on which wish ssh will run")
+	maxSessions := flag.Int("max-sessions", 200, "max concurrent sessions overall (0 = unlimited)")
+	maxPerIP := flag.Int("max-sessions-per-ip", 4, "max concurrent sessions per source IP (0 = unlimited)")
+	connRate := flag.Float64("conn-rate", 0.5, "new connections per second allowed per IP (0 = unlimited)")
+	connBurst := flag.Int("conn-burst", 5, "new connections an IP may open back to back")
+	idleTimeout := flag.Duration("idle-timeout", 5*time.Minute, "disconnect after this long without input (0 = never)")
+	maxTimeout := flag.Duration("max-timeout", 30*time.Minute, "disconnect this long after connecting (0 = never)")
+	eventsPath := flag.String("events", "events.jsonl", "visitor analytics log (empty = disabled)")
+	dataPath := flag.String("data", "internal/config/data.yaml", "portfolio YAML file")
+	reloadEvery := flag.Duration("reload-every", 5*time.Second, "check the portfolio file for changes this often (0 = only on SIGHUP)")
+	hostKeyDir := flag.String("host-key-dir", ".", "directory holding the ed25519, ecdsa and rsa host keys")
+	banner := flag.String("banner", sshserver.DefaultBanner, "pre-auth banner template (empty = none)")
+	motdPath := flag.String("motd-file", "", "post-login message template file (default: built-in MOTD)")
+	httpAddr := flag.String("http-addr", "", "address for /metrics, /healthz and /readyz (empty = disabled)")
+	guestbookPath := flag.String("guestbook", "guestbook.jsonl", "guestbook log (empty = no Guestbook tab)")
+	guestbookPerKey := flag.Int("guestbook-per-key", 3, "guestbook posts allowed per SSH key per -guestbook-window")
+	guestbookWindow := flag.Duration("guestbook-window", 24*time.Hour, "period -guestbook-per-key applies to")
+	guestbookAutoApprove := flag.Bool("guestbook-auto-approve", false, "show guestbook posts without moderation")
+	guestbookBlocklist := flag.String("guestbook-blocklist", "", "file of words, one per line, that get a guestbook post rejected")
+	contactSMTP := flag.String("contact-smtp", "", "deliver contact form messages through this SMTP relay, host:port (password from $CONTACT_SMTP_PASSWORD)")
+	contactSMTPUser := flag.String("contact-smtp-user", "", "SMTP username (empty = no AUTH)")
+	contactFrom := flag.String("contact-from", "", "sender address for contact form mail")
+	contactTo := flag.String("contact-to", "", "where contact form mail goes (default: contact.email from the portfolio)")
+	contactWebhook := flag.String("contact-webhook", "", "deliver contact form messages as JSON POSTs to this URL")
+	contactMaildir := flag.String("contact-maildir", "", "deliver contact form messages into this maildir")
+	contactSpool := flag.String("contact-spool", "contact-spool", "directory queueing contact form messages until they are delivered")
+	contactPerSender := flag.Int("contact-per-sender", 3, "contact form messages allowed per SSH key (or IP, without one) per -contact-window")
+	contactWindow := flag.Duration("contact-window", 24*time.Hour, "period -contact-per-sender applies to")
+	contactMaxSpool := flag.Int("contact-max-spool", 1000, "undelivered contact form messages to keep before the form refuses new ones")
+	recordDir := flag.String("record-dir", "", "save sessions that ask for it (ssh -t host record) here as asciicasts (empty = never record)")
+	recordAll := flag.Bool("record-all", false, "record every session into -record-dir, not just those that ask")
+	recordMaxAge := flag.Duration("record-max-age", 7*24*time.Hour, "delete recordings older than this (0 = keep)")
+	recordMaxMB := flag.Int64("record-max-mb", 1024, "delete the oldest recordings once -record-dir holds more than this (0 = no cap)")
 
 	flag.Parse()
 
 	addr := fmt.Sprintf(":%d", *port)
-	hostKeyPath := "ssh_host_ed25519"
 
-	srv, err := sshserver.New(addr, hostKeyPath)
+	motd := sshserver.DefaultMOTD
+	if *motdPath != "" {
+		b, err := os.ReadFile(*motdPath)
+		if err != nil {
+			log.Fatalf("failed to read motd: %v", err)
+		}
+		motd = string(b)
+	}
+
+	var events analytics.Sink
+	if *eventsPath != "" {
+		f, err := analytics.OpenFile(*eventsPath)
+		if err != nil {
+			log.Fatalf("failed to open event log: %v", err)
+		}
+		defer f.Close()
+		events = f
+	}
+
+	var book *guestbook.Book
+	if *guestbookPath != "" {
+		opts := guestbook.Options{
+			PerKey:      *guestbookPerKey,
+			Window:      *guestbookWindow,
+			AutoApprove: *guestbookAutoApprove,
+		}
+		if *guestbookBlocklist != "" {
+			filter, err := guestbook.LoadWordFilter(*guestbookBlocklist)
+			if err != nil {
+				log.Fatalf("failed to read guestbook blocklist: %v", err)
+			}
+			opts.Filter = filter
+		}
+		b, err := guestbook.Open(*guestbookPath, opts)
+		if err != nil {
+			log.Fatalf("failed to open guestbook: %v", err)
+		}
+		defer b.Close()
+		book = b
+	}
+
+	var recordings *recording.Dir
+	if *recordDir != "" {
+		d, err := recording.Open(*recordDir, recording.Options{
+			All:      *recordAll,
+			MaxAge:   *recordMaxAge,
+			MaxBytes: *recordMaxMB << 20,
+		})
+		if err != nil {
+			log.Fatalf("failed to open recording directory: %v", err)
+		}
+		recordings = d
+	}
+
+	store, err := portfolio.Open(*dataPath)
+	metrics.PortfolioLoaded(err)
+	if err != nil {
+		log.Fatalf("failed to load portfolio: %v", err)
+	}
+	go watchPortfolio(store, *reloadEvery)
+
+	queue := openContactQueue(contactFlags{
+		smtp:      *contactSMTP,
+		smtpUser:  *contactSMTPUser,
+		from:      *contactFrom,
+		to:        *contactTo,
+		webhook:   *contactWebhook,
+		maildir:   *contactMaildir,
+		spool:     *contactSpool,
+		perSender: *contactPerSender,
+		window:    *contactWindow,
+		maxSpool:  *contactMaxSpool,
+	}, store.Current())
+
+	cfg := sshserver.Config{
+		Addr:       addr,
+		HostKeyDir: *hostKeyDir,
+		Portfolio:  store,
+		Limits: sshserver.Limits{
+			MaxSessions:      *maxSessions,
+			MaxSessionsPerIP: *maxPerIP,
+			ConnRate:         *connRate,
+			ConnBurst:        *connBurst,
+		},
+		IdleTimeout: *idleTimeout,
+		MaxTimeout:  *maxTimeout,
+		Events:      events,
+		Guestbook:   book,
+		Contact:     queue,
+		Recordings:  recordings,
+		Banner:      *banner,
+		MOTD:        motd,
+	}
+
+	srv, err := sshserver.New(cfg)
 	if err != nil {
 		log.Fatalf("failed to create ssh server: %v", err)
 	}
 
+	if *httpAddr != "" {
+		go func() {
+			log.Printf("Serving metrics and health checks on %s ...", *httpAddr)
+			if err := http.ListenAndServe(*httpAddr, sshserver.HTTPHandler(cfg)); err != nil {
+				log.Fatalf("http server error: %v", err)
+			}
+		}()
+	}
+
 	log.Printf("Starting SSH server on %s ...", addr)
 
 	if err := srv.ListenAndServe(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// watchPortfolio swaps in a new portfolio snapshot whenever the file
+// changes, checking every interval and on SIGHUP. Sessions already running
+// keep the snapshot they started with.
+func watchPortfolio(store *portfolio.Store, every time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if every > 0 {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		select {
+		case <-hup:
+		case <-tick:
+		}
+		changed, err := store.Reload()
+		if err != nil {
+			metrics.PortfolioLoaded(err)
+			log.Printf("failed to reload portfolio, keeping version %d: %v", store.Current().Version, err)
+			continue
+		}
+		if changed {
+			metrics.PortfolioLoaded(nil)
+			log.Printf("Loaded portfolio version %d", store.Current().Version)
+		}
+	}
+}
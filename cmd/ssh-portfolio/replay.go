@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/Shbhom/ssh-portfolio/internal/recording"
+)
+
+// resetTerminal leaves the alternate screen, shows the cursor and resets
+// colors, in case the recording stops before the app restored them.
+const resetTerminal = "\x1b[?1049l\x1b[?25h\x1b[0m"
+
+// runReplay implements `ssh-portfolio replay`: play a session recording in
+// this terminal.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := fs.Float64("speed", 1, "playback speed, 2 = twice as fast")
+	maxIdle := fs.Duration("max-idle", 0, "shorten pauses to at most this long (0 = as recorded)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: ssh-portfolio replay [flags] FILE.cast")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("failed to open recording: %v", err)
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = recording.Play(ctx, f, os.Stdout, recording.PlayOptions{Speed: *speed, MaxIdle: *maxIdle})
+	fmt.Print(resetTerminal)
+	if err != nil && ctx.Err() == nil {
+		log.Fatalf("failed to replay %s: %v", fs.Arg(0), err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/analytics"
+)
+
+// runStats implements `ssh-portfolio stats`: a quick summary of the event log.
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	events := fs.String("events", "events.jsonl", "event log written by the server")
+	top := fs.Int("top", 10, "how many projects to list")
+	fs.Parse(args)
+
+	f, err := os.Open(*events)
+	if err != nil {
+		log.Fatalf("failed to open event log: %v", err)
+	}
+	defer f.Close()
+
+	sum, err := analytics.Summarize(f)
+	if err != nil {
+		log.Fatalf("failed to read event log: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Sessions\t%d\n", sum.Sessions)
+	fmt.Fprintf(w, "Average session\t%s\n", sum.AvgSession.Round(time.Second))
+
+	fmt.Fprintln(w, "\nDay\tVisitors")
+	for _, d := range sum.VisitorsPerDay {
+		fmt.Fprintf(w, "%s\t%d\n", d.Day, d.Visitors)
+	}
+
+	fmt.Fprintln(w, "\nProject\tViews")
+	for i, p := range sum.TopProjects {
+		if i >= *top {
+			break
+		}
+		fmt.Fprintf(w, "%s\t%d\n", p.Project, p.Views)
+	}
+
+	w.Flush()
+}
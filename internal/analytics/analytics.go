@@ -0,0 +1,140 @@
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Event kinds emitted by the UI.
+const (
+	KindSessionStart = "session_start"
+	KindTabView      = "tab_view"
+	KindProjectView  = "project_view"
+	KindSearch       = "search"
+	KindQuit         = "quit"
+)
+
+// Event is one line of the event log. Fields that don't apply to a kind are
+// left empty.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Session string    `json:"session"`
+	Visitor string    `json:"visitor"`
+
+	// session_start
+	User          string `json:"user,omitempty"`
+	ClientVersion string `json:"client_version,omitempty"`
+	Term          string `json:"term,omitempty"`
+	Width         int    `json:"width,omitempty"`
+	Height        int    `json:"height,omitempty"`
+
+	Tab      string  `json:"tab,omitempty"`      // tab_view
+	Project  string  `json:"project,omitempty"`  // project_view
+	Query    string  `json:"query,omitempty"`    // search
+	Reason   string  `json:"reason,omitempty"`   // quit
+	Duration float64 `json:"duration,omitempty"` // seconds, tab_view and quit
+}
+
+// Sink receives events. Implementations must be safe for concurrent use since
+// every session writes to the same sink.
+type Sink interface {
+	Emit(Event)
+	Close() error
+}
+
+// FileSink appends events to a JSONL file.
+type FileSink struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func OpenFile(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (s *FileSink) Emit(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.enc.Encode(e) // analytics must never take a session down
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// Recorder stamps every event with the session it belongs to. A nil Recorder
+// drops everything, so callers don't need to check whether analytics is on.
+type Recorder struct {
+	sink    Sink
+	session string
+	visitor string
+}
+
+// NewRecorder returns a Recorder for one session. remoteIP is turned into a
+// visitor ID that only means anything for the day, see visitorID.
+func NewRecorder(sink Sink, remoteIP string) *Recorder {
+	if sink == nil {
+		return nil
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return &Recorder{
+		sink:    sink,
+		session: hex.EncodeToString(id),
+		visitor: visitorID(remoteIP, time.Now()),
+	}
+}
+
+// salt keys the visitor ID HMAC. It is random, kept only in memory and
+// replaced every UTC day: hashing every address can't undo an ID without
+// it, and IDs don't follow a visitor from one day to the next. Counting
+// visitors per day is all the log needs them for. A restart draws a new
+// salt, so a visitor seen before and after it counts twice that day.
+var salt struct {
+	mu  sync.Mutex
+	day string
+	key []byte
+}
+
+func visitorID(ip string, now time.Time) string {
+	day := now.UTC().Format(time.DateOnly)
+
+	salt.mu.Lock()
+	if salt.day != day {
+		salt.day = day
+		salt.key = make([]byte, 32)
+		_, _ = rand.Read(salt.key)
+	}
+	key := salt.key
+	salt.mu.Unlock()
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+func (r *Recorder) Emit(e Event) {
+	if r == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Session = r.session
+	e.Visitor = r.visitor
+	r.sink.Emit(e)
+}
@@ -0,0 +1,113 @@
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Summary is what `ssh-portfolio stats` prints.
+type Summary struct {
+	Sessions       int
+	VisitorsPerDay []DayCount
+	TopProjects    []ProjectCount
+	AvgSession     time.Duration
+}
+
+type DayCount struct {
+	Day      string // YYYY-MM-DD, UTC
+	Visitors int
+}
+
+type ProjectCount struct {
+	Project string
+	Views   int
+}
+
+// Summarize reads a JSONL event log. Lines that don't parse are skipped so a
+// log truncated mid-write still summarizes.
+func Summarize(r io.Reader) (Summary, error) {
+	type span struct {
+		first, last time.Time
+		quit        float64
+	}
+
+	days := map[string]map[string]bool{}
+	projects := map[string]int{}
+	sessions := map[string]*span{}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+
+		sp, ok := sessions[e.Session]
+		if !ok {
+			sp = &span{first: e.Time}
+			sessions[e.Session] = sp
+		}
+		if e.Time.Before(sp.first) {
+			sp.first = e.Time
+		}
+		if e.Time.After(sp.last) {
+			sp.last = e.Time
+		}
+
+		switch e.Kind {
+		case KindSessionStart:
+			day := e.Time.UTC().Format("2006-01-02")
+			if days[day] == nil {
+				days[day] = map[string]bool{}
+			}
+			days[day][e.Visitor] = true
+		case KindProjectView:
+			projects[e.Project]++
+		case KindQuit:
+			sp.quit = e.Duration
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Summary{}, err
+	}
+
+	var s Summary
+	for day, visitors := range days {
+		s.VisitorsPerDay = append(s.VisitorsPerDay, DayCount{Day: day, Visitors: len(visitors)})
+	}
+	sort.Slice(s.VisitorsPerDay, func(i, j int) bool {
+		return s.VisitorsPerDay[i].Day < s.VisitorsPerDay[j].Day
+	})
+
+	for name, n := range projects {
+		s.TopProjects = append(s.TopProjects, ProjectCount{Project: name, Views: n})
+	}
+	sort.Slice(s.TopProjects, func(i, j int) bool {
+		a, b := s.TopProjects[i], s.TopProjects[j]
+		if a.Views != b.Views {
+			return a.Views > b.Views
+		}
+		return a.Project < b.Project
+	})
+
+	// Sessions cut off by a dropped connection never emit quit, so fall back
+	// to the span between their first and last event.
+	var total time.Duration
+	for _, sp := range sessions {
+		if sp.quit > 0 {
+			total += time.Duration(sp.quit * float64(time.Second))
+		} else {
+			total += sp.last.Sub(sp.first)
+		}
+	}
+	s.Sessions = len(sessions)
+	if s.Sessions > 0 {
+		s.AvgSession = total / time.Duration(s.Sessions)
+	}
+
+	return s, nil
+}
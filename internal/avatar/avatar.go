@@ -0,0 +1,125 @@
+// Package avatar shrinks a profile picture into pixel grids small enough to
+// draw in a terminal cell by cell.
+//
+// Each size has two grids: one with two pixels per cell, for colored half
+// blocks, and one with 2x4 pixels per cell, for braille dots on terminals
+// without color. Drawing them is up to the caller, who knows the session's
+// color profile.
+package avatar
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // decoders for image.Decode
+	_ "image/png"
+	"os"
+)
+
+// Heights offered, in terminal rows.
+var Heights = []int{4, 6, 8}
+
+// maxSide guards against decoding a huge image into memory.
+const maxSide = 8192
+
+// Avatar is a picture at every height in Heights, smallest first.
+type Avatar struct {
+	Sizes []*Picture
+}
+
+// Picture is the avatar at one size.
+type Picture struct {
+	Cols, Rows int // in terminal cells
+
+	half []color.NRGBA // Cols x Rows*2, row by row
+	dots []color.NRGBA // Cols*2 x Rows*4, row by row
+}
+
+// Half is the pixel at x, y of the half-block grid, Cols x Rows*2.
+func (p *Picture) Half(x, y int) color.NRGBA {
+	return p.half[y*p.Cols+x]
+}
+
+// Dot is the pixel at x, y of the braille grid, Cols*2 x Rows*4.
+func (p *Picture) Dot(x, y int) color.NRGBA {
+	return p.dots[y*p.Cols*2+x]
+}
+
+// Load reads a PNG or JPEG and shrinks it to every size in Heights.
+func Load(path string) (*Avatar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width > maxSide || cfg.Height > maxSide {
+		return nil, fmt.Errorf("%s is %dx%d, larger than %dx%d", path, cfg.Width, cfg.Height, maxSide, maxSide)
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return New(img)
+}
+
+// New shrinks img to every size in Heights.
+func New(img image.Image) (*Avatar, error) {
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, errors.New("image is empty")
+	}
+
+	a := &Avatar{}
+	for _, rows := range Heights {
+		// A cell is about twice as tall as it is wide, so two pixels per
+		// cell down and one across keeps pixels square. Wide pictures are
+		// capped at 2:1.
+		cols := max(1, min(rows*4, (rows*2*b.Dx()+b.Dy()/2)/b.Dy()))
+		a.Sizes = append(a.Sizes, &Picture{
+			Cols: cols,
+			Rows: rows,
+			half: shrink(img, cols, rows*2),
+			dots: shrink(img, cols*2, rows*4),
+		})
+	}
+	return a, nil
+}
+
+// shrink scales img to w x h by averaging the source pixels under each
+// target pixel. Averaging is done premultiplied, so transparent pixels
+// don't darken their neighbours.
+func shrink(img image.Image, w, h int) []color.NRGBA {
+	b := img.Bounds()
+	out := make([]color.NRGBA, w*h)
+	for y := range h {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/h)
+		for x := range w {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/w)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			avg := color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			}
+			out[y*w+x] = color.NRGBAModel.Convert(avg).(color.NRGBA)
+		}
+	}
+	return out
+}
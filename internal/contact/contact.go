@@ -0,0 +1,85 @@
+// Package contact delivers messages sent from the Contact tab's form.
+//
+// Messages are spooled to disk by a Queue and handed to a Notifier (SMTP, an
+// HTTP webhook or a maildir), so a visitor never waits on delivery and a
+// failed send is retried later, across restarts too.
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field limits, in characters.
+const (
+	MaxName    = 100
+	MaxReplyTo = 254
+	MaxBody    = 1000
+)
+
+// Message is one submission of the contact form.
+type Message struct {
+	Time        time.Time `json:"time"`
+	Name        string    `json:"name"`
+	ReplyTo     string    `json:"reply_to"`
+	Body        string    `json:"body"`
+	User        string    `json:"user"`                  // SSH username
+	Fingerprint string    `json:"fingerprint,omitempty"` // SSH key, if they used one
+	IP          string    `json:"ip,omitempty"`          // where they connected from
+}
+
+// Notifier delivers a message to the portfolio owner.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// Validate cleans m's fields and checks them, returning the first problem
+// in words a visitor can act on.
+func Validate(m Message) (Message, error) {
+	m.Name = clean(m.Name)
+	m.ReplyTo = clean(m.ReplyTo)
+	m.Body = clean(m.Body)
+	m.User = clean(m.User)
+
+	switch {
+	case m.Name == "":
+		return m, errors.New("please add your name")
+	case utf8.RuneCountInString(m.Name) > MaxName:
+		return m, fmt.Errorf("name is longer than %d characters", MaxName)
+	case m.ReplyTo == "":
+		return m, errors.New("please add an email address to reply to")
+	case len(m.ReplyTo) > MaxReplyTo:
+		return m, errors.New("email address is too long")
+	case m.Body == "":
+		return m, errors.New("message is empty")
+	case utf8.RuneCountInString(m.Body) > MaxBody:
+		return m, fmt.Errorf("message is longer than %d characters", MaxBody)
+	}
+
+	addr, err := mail.ParseAddress(m.ReplyTo)
+	if err != nil || addr.Address != m.ReplyTo || !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@"):], ".") {
+		return m, errors.New("that doesn't look like an email address")
+	}
+	return m, nil
+}
+
+// clean drops control characters, so nothing a visitor types can break out
+// of a mail header or a terminal, and trims and folds whitespace.
+func clean(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), r == utf8.RuneError:
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,190 @@
+package contact
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SMTP sends messages as plain-text mail. The visitor's address goes in
+// Reply-To; From is always the configured sender, so SPF and DKIM line up.
+type SMTP struct {
+	Addr     string // host:port of the relay
+	From     string
+	To       string
+	Username string // empty for no AUTH
+	Password string
+}
+
+func (s SMTP) Notify(ctx context.Context, m Message) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+
+	// net/smtp has no context support, so bind its connection to ctx
+	// instead: when ctx ends the connection closes, and the transaction
+	// with it, rather than finishing behind a retry.
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if err := s.send(c, host, m); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// send is smtp.SendMail on an open client.
+func (s SMTP) send(c *smtp.Client, host string, m Message) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(s.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.compose(m)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	// the relay has the message now; a failed goodbye is no reason to
+	// send it again
+	c.Quit()
+	return nil
+}
+
+func (s SMTP) compose(m Message) []byte {
+	replyTo := (&mail.Address{Name: m.Name, Address: m.ReplyTo}).String()
+	subject := mime.QEncoding.Encode("utf-8", "Portfolio message from "+m.Name)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", s.To)
+	fmt.Fprintf(&b, "Reply-To: %s\r\n", replyTo)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", m.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&b, "Content-Transfer-Encoding: 8bit\r\n")
+	fmt.Fprintf(&b, "\r\n")
+	b.WriteString(body(m, "\r\n"))
+	return b.Bytes()
+}
+
+// Webhook POSTs each message as JSON. Any 2xx response counts as delivered.
+type Webhook struct {
+	URL    string
+	Client *http.Client // nil for http.DefaultClient
+}
+
+func (w Webhook) Notify(ctx context.Context, m Message) error {
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// Maildir writes each message as a mail file into a maildir, for hosts where
+// the owner reads mail locally or syncs it elsewhere.
+type Maildir struct {
+	Dir string
+}
+
+func (d Maildir) Notify(ctx context.Context, m Message) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(d.Dir, sub), 0o700); err != nil {
+			return err
+		}
+	}
+
+	// write to tmp, then move into new, so readers never see half a file
+	name := fmt.Sprintf("%d.%s.ssh-portfolio", m.Time.UnixNano(), randomHex(6))
+	tmp := filepath.Join(d.Dir, "tmp", name)
+	local := SMTP{From: "ssh-portfolio@localhost", To: "owner@localhost"}
+	if err := os.WriteFile(tmp, local.compose(m), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(d.Dir, "new", name))
+}
+
+// body is the plain-text message the owner reads.
+func body(m Message, nl string) string {
+	lines := []string{
+		m.Body,
+		"",
+		"--",
+		fmt.Sprintf("%s <%s>", m.Name, m.ReplyTo),
+		"SSH user: " + m.User,
+	}
+	if m.Fingerprint != "" {
+		lines = append(lines, "SSH key: "+m.Fingerprint)
+	}
+	return strings.Join(lines, nl) + nl
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
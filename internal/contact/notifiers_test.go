@@ -0,0 +1,179 @@
+package contact
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testMessage = Message{
+	Time:        time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC),
+	Name:        "Ada Lovelace",
+	ReplyTo:     "ada@example.com",
+	Body:        "Shall we talk engines?",
+	User:        "ada",
+	Fingerprint: "SHA256:abc",
+	IP:          "192.0.2.1",
+}
+
+// smtpServer is a stand-in relay. It accepts every message and passes its
+// DATA on mail, or, when stall is set, never answers the end of DATA.
+type smtpServer struct {
+	addr   string
+	stall  bool
+	mail   chan string
+	closed chan struct{} // a client hung up
+}
+
+func startSMTP(t *testing.T, stall bool) *smtpServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpServer{
+		addr:   ln.Addr().String(),
+		stall:  stall,
+		mail:   make(chan string, 10),
+		closed: make(chan struct{}, 10),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 stand-in ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			s.closed <- struct{}{}
+			return
+		}
+		verb, _, _ := strings.Cut(strings.ToUpper(line), " ")
+		switch verb {
+		case "EHLO", "HELO", "MAIL", "RCPT", "RSET", "NOOP":
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				s.closed <- struct{}{}
+				return
+			}
+			if s.stall {
+				// hold the reply until the client gives up
+				_, err := bufio.NewReader(conn).ReadByte()
+				if err != nil {
+					s.closed <- struct{}{}
+				}
+				return
+			}
+			s.mail <- string(data)
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	relay := startSMTP(t, false)
+	s := SMTP{Addr: relay.addr, From: "portfolio@example.com", To: "owner@example.com"}
+	if err := s.Notify(context.Background(), testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-relay.mail
+	for _, want := range []string{
+		"From: portfolio@example.com\n",
+		"To: owner@example.com\n",
+		`Reply-To: "Ada Lovelace" <ada@example.com>` + "\n",
+		"Subject: Portfolio message from Ada Lovelace\n",
+		"\nShall we talk engines?\n",
+		"SSH key: SHA256:abc\n",
+	} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("mail lacks %q:\n%s", want, mail)
+		}
+	}
+}
+
+// A send that times out must be abandoned, not left to finish while the
+// queue sends the message again.
+func TestSMTPNotifyTimeout(t *testing.T) {
+	relay := startSMTP(t, true)
+	s := SMTP{Addr: relay.addr, From: "portfolio@example.com", To: "owner@example.com"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := s.Notify(ctx, testMessage)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Notify = %v, want the deadline", err)
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Errorf("Notify returned %s after its deadline", took)
+	}
+
+	select {
+	case <-relay.closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the connection was left open after the timeout")
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	got := make(chan Message, 1)
+	var status atomic.Int32
+	status.Store(http.StatusNoContent)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var m Message
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Error(err)
+		}
+		got <- m
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+	w := Webhook{URL: srv.URL, Client: srv.Client()}
+
+	if err := w.Notify(context.Background(), testMessage); err != nil {
+		t.Fatal(err)
+	}
+	if m := <-got; m != testMessage {
+		t.Errorf("posted %+v, want %+v", m, testMessage)
+	}
+
+	status.Store(http.StatusBadGateway)
+	if err := w.Notify(context.Background(), testMessage); err == nil {
+		t.Error("a 502 counted as delivered")
+	}
+	<-got
+}
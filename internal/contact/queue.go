@@ -0,0 +1,260 @@
+package contact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrTooMany   = errors.New("too many messages from you, try again later")
+	ErrSpoolFull = errors.New("too many messages are waiting to be delivered, try again later")
+)
+
+// Retry schedule: the first retry after retryBase, doubling up to retryMax,
+// giving up after maxAttempts.
+const (
+	retryBase   = 30 * time.Second
+	retryMax    = time.Hour
+	maxAttempts = 24
+	sendTimeout = 30 * time.Second
+)
+
+// senderGC is how many senders the limiter tracks before it forgets the
+// ones whose window has passed.
+const senderGC = 1024
+
+// Options tunes a Queue. The zero value allows 3 messages per sender per
+// day and 1000 messages waiting in the spool.
+type Options struct {
+	PerSender int           // messages one SSH key, or IP without a key, may send per Window
+	Window    time.Duration // period PerSender applies to
+	MaxSpool  int           // undelivered messages kept before new ones are refused
+}
+
+// spooled is one queued message as stored on disk.
+type spooled struct {
+	Message   Message   `json:"message"`
+	Attempts  int       `json:"attempts"`
+	NextTry   time.Time `json:"next_try"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Queue spools messages to a directory and delivers them in the background.
+// A message stays in the spool until its notifier accepts it; after
+// maxAttempts failures it moves to the spool's failed/ subdirectory.
+type Queue struct {
+	dir    string
+	notify Notifier
+	opts   Options
+	wake   chan struct{}
+
+	mu   sync.Mutex
+	sent map[string][]time.Time // recent send times per sender
+}
+
+// NewQueue uses dir as the spool, creating it if needed. Messages left in it
+// by an earlier run are delivered once Run starts.
+func NewQueue(dir string, n Notifier, opts Options) (*Queue, error) {
+	if opts.PerSender <= 0 {
+		opts.PerSender = 3
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+	if opts.MaxSpool <= 0 {
+		opts.MaxSpool = 1000
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "failed"), 0o700); err != nil {
+		return nil, err
+	}
+	return &Queue{
+		dir:    dir,
+		notify: n,
+		opts:   opts,
+		wake:   make(chan struct{}, 1),
+		sent:   map[string][]time.Time{},
+	}, nil
+}
+
+// Enqueue validates m and spools it for delivery. It refuses the message
+// with ErrTooMany if its sender has used up their allowance, and with
+// ErrSpoolFull if delivery is too far behind.
+func (q *Queue) Enqueue(m Message) error {
+	m, err := Validate(m)
+	if err != nil {
+		return err
+	}
+	if m.Time.IsZero() {
+		m.Time = time.Now()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := sender(m)
+	recent := q.recent(key, m.Time)
+	if len(recent) >= q.opts.PerSender {
+		return ErrTooMany
+	}
+	if q.spooled() >= q.opts.MaxSpool {
+		return ErrSpoolFull
+	}
+
+	item := spooled{Message: m, NextTry: m.Time}
+	name := m.Time.UTC().Format("20060102T150405.000000000") + "-" + randomHex(4) + ".json"
+	if err := q.write(name, item); err != nil {
+		return err
+	}
+	q.sent[key] = append(recent, m.Time)
+	if len(q.sent) > senderGC {
+		q.prune(m.Time)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default: // a wake-up is already pending
+	}
+	return nil
+}
+
+// sender is who a message counts against: their SSH key if they used one,
+// otherwise where they connected from.
+func sender(m Message) string {
+	if m.Fingerprint != "" {
+		return m.Fingerprint
+	}
+	return "ip:" + m.IP
+}
+
+// recent returns key's send times within the window before now.
+func (q *Queue) recent(key string, now time.Time) []time.Time {
+	var recent []time.Time
+	for _, t := range q.sent[key] {
+		if now.Sub(t) < q.opts.Window {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// prune forgets senders with nothing sent within the window.
+func (q *Queue) prune(now time.Time) {
+	for key := range q.sent {
+		if recent := q.recent(key, now); len(recent) > 0 {
+			q.sent[key] = recent
+		} else {
+			delete(q.sent, key)
+		}
+	}
+}
+
+// spooled counts the messages waiting for delivery.
+func (q *Queue) spooled() int {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return 0 // the write that follows will fail too, and say why
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			n++
+		}
+	}
+	return n
+}
+
+// Run delivers spooled messages until stop is closed.
+func (q *Queue) Run(stop <-chan struct{}) {
+	t := time.NewTicker(retryBase / 2)
+	defer t.Stop()
+	for {
+		q.flush(time.Now())
+		select {
+		case <-stop:
+			return
+		case <-q.wake:
+		case <-t.C:
+		}
+	}
+}
+
+// flush tries every message that is due.
+func (q *Queue) flush(now time.Time) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		log.Printf("contact: failed to read spool: %v", err)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(q.dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var item spooled
+		if err := json.Unmarshal(data, &item); err != nil {
+			log.Printf("contact: unreadable spool file %s, moving it aside: %v", e.Name(), err)
+			os.Rename(path, filepath.Join(q.dir, "failed", e.Name()))
+			continue
+		}
+		if now.Before(item.NextTry) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err = q.notify.Notify(ctx, item.Message)
+		cancel()
+		if err == nil {
+			os.Remove(path)
+			continue
+		}
+
+		item.Attempts++
+		item.LastError = err.Error()
+		item.NextTry = now.Add(backoff(item.Attempts))
+		if item.Attempts >= maxAttempts {
+			log.Printf("contact: giving up on %s after %d attempts: %v", e.Name(), item.Attempts, err)
+			q.write(filepath.Join("failed", e.Name()), item)
+			os.Remove(path)
+			continue
+		}
+		log.Printf("contact: delivery of %s failed (attempt %d), retrying at %s: %v",
+			e.Name(), item.Attempts, item.NextTry.Format(time.TimeOnly), err)
+		q.write(e.Name(), item)
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
+
+// write stores item under name in the spool, via a temp file and rename so
+// a crash never leaves half a message.
+func (q *Queue) write(name string, item spooled) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(q.dir, "."+filepath.Base(name)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(q.dir, name))
+}
@@ -0,0 +1,96 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// notifyFunc adapts a function to a Notifier.
+type notifyFunc func(context.Context, Message) error
+
+func (f notifyFunc) Notify(ctx context.Context, m Message) error { return f(ctx, m) }
+
+func TestQueueLimits(t *testing.T) {
+	q, err := NewQueue(t.TempDir(), nil, Options{PerSender: 2, Window: time.Hour, MaxSpool: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := testMessage.Time
+	send := func(fingerprint, ip string, at time.Time) error {
+		m := testMessage
+		m.Fingerprint, m.IP, m.Time = fingerprint, ip, at
+		return q.Enqueue(m)
+	}
+
+	for i := range 2 {
+		if err := send("SHA256:one", "192.0.2.1", now); err != nil {
+			t.Fatalf("message %d: %v", i+1, err)
+		}
+	}
+	if err := send("SHA256:one", "192.0.2.9", now); !errors.Is(err, ErrTooMany) {
+		t.Errorf("a third message from the same key = %v", err)
+	}
+	// the same address with another key is someone else
+	if err := send("SHA256:two", "192.0.2.1", now); err != nil {
+		t.Errorf("another key from the same address = %v", err)
+	}
+	// without a key, the address is the sender
+	if err := send("", "192.0.2.2", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := send("", "192.0.2.2", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := send("", "192.0.2.2", now); !errors.Is(err, ErrTooMany) {
+		t.Errorf("a third keyless message from one address = %v", err)
+	}
+
+	// five are spooled now, the cap
+	if err := send("SHA256:three", "192.0.2.3", now); !errors.Is(err, ErrSpoolFull) {
+		t.Errorf("a message past the spool cap = %v", err)
+	}
+	q.notify = notifyFunc(func(context.Context, Message) error { return nil })
+	q.flush(now)
+	if err := send("SHA256:one", "192.0.2.1", now.Add(time.Hour)); err != nil {
+		t.Errorf("after delivery and a window, = %v", err)
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+	q, err := NewQueue(t.TempDir(), notifyFunc(func(context.Context, Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			return errors.New("relay down")
+		}
+		return nil
+	}), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	now := testMessage.Time
+	q.flush(now)
+	if n := q.spooled(); n != 1 {
+		t.Fatalf("%d spooled after a failed send, want 1", n)
+	}
+	q.flush(now.Add(retryBase / 2))
+	if attempts != 1 {
+		t.Errorf("retried after %s, before the backoff", retryBase/2)
+	}
+	q.flush(now.Add(retryBase))
+	if attempts != 2 || q.spooled() != 0 {
+		t.Errorf("after the backoff: %d attempts, %d spooled", attempts, q.spooled())
+	}
+}
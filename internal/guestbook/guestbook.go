@@ -0,0 +1,350 @@
+// Package guestbook stores visitor messages in an append-only JSONL log.
+//
+// Every change is a new line: a post, an approval or a deletion. The server
+// and the `ssh-portfolio guestbook` admin command append to the same file,
+// and each picks up the other's lines the next time it reads.
+package guestbook
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLen is the longest message, in characters.
+const MaxLen = 200
+
+var (
+	ErrEmpty    = errors.New("message is empty")
+	ErrTooLong  = fmt.Errorf("message is longer than %d characters", MaxLen)
+	ErrNoKey    = errors.New("posting needs an SSH key")
+	ErrTooMany  = errors.New("too many messages from this key, try again later")
+	ErrNotFound = errors.New("no such entry")
+)
+
+// Log operations.
+const (
+	opPost    = "post"
+	opApprove = "approve"
+	opDelete  = "delete"
+)
+
+// record is one line of the log.
+type record struct {
+	Op          string    `json:"op"`
+	ID          string    `json:"id"`
+	Time        time.Time `json:"time"`
+	Fingerprint string    `json:"fingerprint,omitempty"` // post
+	User        string    `json:"user,omitempty"`        // post
+	Message     string    `json:"message,omitempty"`     // post
+}
+
+// Entry is one message as it stands after all moderation.
+type Entry struct {
+	ID          string
+	Time        time.Time
+	Fingerprint string // SHA256 fingerprint of the visitor's SSH key
+	User        string
+	Message     string
+	Approved    bool
+}
+
+// Filter vets a message before it is stored. Returning an error rejects the
+// post; the error is shown to the visitor.
+type Filter func(message string) error
+
+// Options tunes a Book. The zero value needs approval for every post and
+// allows 3 posts per key per day.
+type Options struct {
+	PerKey      int           // posts one key may make per Window
+	Window      time.Duration // period PerKey applies to
+	AutoApprove bool          // show posts without moderation
+	Filter      Filter        // nil accepts everything
+}
+
+// Book is a guestbook backed by a log file. It is safe for concurrent use.
+type Book struct {
+	opts Options
+
+	mu      sync.Mutex
+	f       *os.File
+	offset  int64 // how much of the file has been read
+	entries []*Entry
+	byID    map[string]*Entry
+	posted  map[string][]time.Time // post times per key, deleted posts included
+}
+
+// Open reads the log at path, creating it if needed.
+func Open(path string, opts Options) (*Book, error) {
+	if opts.PerKey <= 0 {
+		opts.PerKey = 3
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	b := &Book{opts: opts, f: f, byID: map[string]*Entry{}, posted: map[string][]time.Time{}}
+	if err := b.catchUp(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Book) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.f.Close()
+}
+
+// Post stores a message from the visitor with the given key fingerprint.
+func (b *Book) Post(fingerprint, user, message string, now time.Time) (Entry, error) {
+	if fingerprint == "" {
+		return Entry{}, ErrNoKey
+	}
+	message = Clean(message)
+	switch {
+	case message == "":
+		return Entry{}, ErrEmpty
+	case utf8.RuneCountInString(message) > MaxLen:
+		return Entry{}, ErrTooLong
+	}
+	if b.opts.Filter != nil {
+		if err := b.opts.Filter(message); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.catchUp(); err != nil {
+		return Entry{}, err
+	}
+
+	recent := 0
+	for _, t := range b.posted[fingerprint] {
+		if now.Sub(t) < b.opts.Window {
+			recent++
+		}
+	}
+	if recent >= b.opts.PerKey {
+		return Entry{}, ErrTooMany
+	}
+
+	rec := record{
+		Op:          opPost,
+		ID:          newID(),
+		Time:        now,
+		Fingerprint: fingerprint,
+		User:        Clean(user),
+		Message:     message,
+	}
+	if err := b.append(rec); err != nil {
+		return Entry{}, err
+	}
+	if b.opts.AutoApprove {
+		if err := b.append(record{Op: opApprove, ID: rec.ID, Time: now}); err != nil {
+			return Entry{}, err
+		}
+	}
+	return *b.byID[rec.ID], nil
+}
+
+// Approve makes an entry visible to everyone.
+func (b *Book) Approve(id string, now time.Time) error {
+	return b.moderate(opApprove, id, now)
+}
+
+// Delete removes an entry, approved or not.
+func (b *Book) Delete(id string, now time.Time) error {
+	return b.moderate(opDelete, id, now)
+}
+
+func (b *Book) moderate(op, id string, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.catchUp(); err != nil {
+		return err
+	}
+	if _, ok := b.byID[id]; !ok {
+		return ErrNotFound
+	}
+	return b.append(record{Op: op, ID: id, Time: now})
+}
+
+// Latest is up to n approved entries, newest first.
+func (b *Book) Latest(n int) []Entry {
+	return b.list(n, func(e *Entry) bool { return e.Approved })
+}
+
+// Pending is every entry waiting for approval, newest first.
+func (b *Book) Pending() []Entry {
+	return b.list(0, func(e *Entry) bool { return !e.Approved })
+}
+
+// All is every entry that hasn't been deleted, newest first.
+func (b *Book) All() []Entry {
+	return b.list(0, func(*Entry) bool { return true })
+}
+
+// list walks entries newest first; n <= 0 means no limit.
+func (b *Book) list(n int, keep func(*Entry) bool) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_ = b.catchUp() // a failed read still leaves what we had
+
+	var out []Entry
+	for i := len(b.entries) - 1; i >= 0; i-- {
+		if n > 0 && len(out) == n {
+			break
+		}
+		if e := b.entries[i]; keep(e) {
+			out = append(out, *e)
+		}
+	}
+	return out
+}
+
+// catchUp applies lines appended since the last read, by us or by another
+// process. Callers hold mu.
+func (b *Book) catchUp() error {
+	info, err := b.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() <= b.offset {
+		return nil
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(b.f, b.offset, info.Size()-b.offset))
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil // a partial last line is picked up once it is finished
+		}
+		if err != nil {
+			return err
+		}
+		b.offset += int64(len(line))
+
+		var rec record
+		if json.Unmarshal(line, &rec) != nil {
+			continue // skip corrupt lines rather than lose the rest of the book
+		}
+		b.apply(rec)
+	}
+}
+
+func (b *Book) apply(rec record) {
+	switch rec.Op {
+	case opPost:
+		e := &Entry{
+			ID:          rec.ID,
+			Time:        rec.Time,
+			Fingerprint: rec.Fingerprint,
+			User:        rec.User,
+			Message:     rec.Message,
+		}
+		b.entries = append(b.entries, e)
+		b.byID[e.ID] = e
+		b.posted[e.Fingerprint] = append(b.posted[e.Fingerprint], e.Time)
+	case opApprove:
+		if e, ok := b.byID[rec.ID]; ok {
+			e.Approved = true
+		}
+	case opDelete:
+		delete(b.byID, rec.ID)
+		for i, e := range b.entries {
+			if e.ID == rec.ID {
+				b.entries = append(b.entries[:i], b.entries[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// append writes rec and applies it. Callers hold mu and have caught up.
+func (b *Book) append(rec record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := b.f.Write(line); err != nil {
+		return err
+	}
+	return b.catchUp()
+}
+
+func newID() string {
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// Clean makes visitor text safe to show in other visitors' terminals: it
+// drops control characters (and with them any escape sequences), folds
+// whitespace to single spaces and trims the ends.
+func Clean(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), !unicode.IsPrint(r), r == utf8.RuneError:
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// LoadWordFilter reads a WordFilter's words from a file, one per line.
+// Blank lines and lines starting with # are ignored.
+func LoadWordFilter(path string) (Filter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return WordFilter(words), nil
+}
+
+// WordFilter rejects messages containing any of words, compared
+// case-insensitively against whole words.
+func WordFilter(words []string) Filter {
+	blocked := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			blocked[w] = true
+		}
+	}
+	return func(message string) error {
+		for _, w := range strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if blocked[w] {
+				return errors.New("please keep it friendly")
+			}
+		}
+		return nil
+	}
+}
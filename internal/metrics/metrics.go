@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ActiveSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssh_portfolio_active_sessions",
+		Help: "Sessions currently being served.",
+	})
+
+	Connections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssh_portfolio_connections_total",
+		Help: "TCP connections accepted by the SSH server, including those the connection rate limit closes straight away.",
+	})
+
+	HandshakeFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ssh_portfolio_handshake_failures_total",
+		Help: "Connections that failed the SSH handshake.",
+	})
+
+	LimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssh_portfolio_limit_rejections_total",
+		Help: "Visitors turned away, by limit (rate: connections closed on accept; total, ip: sessions over the concurrency caps).",
+	}, []string{"limit"})
+
+	TabViews = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssh_portfolio_tab_views_total",
+		Help: "Times each tab was opened.",
+	}, []string{"tab"})
+
+	RenderSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ssh_portfolio_render_seconds",
+		Help:    "Time spent in View() per frame.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 12), // 100µs .. ~200ms
+	})
+
+	PortfolioLoads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssh_portfolio_portfolio_loads_total",
+		Help: "Portfolio loads from disk, by result.",
+	}, []string{"result"})
+
+	PortfolioLoadFailing = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ssh_portfolio_portfolio_load_failing",
+		Help: "1 while the portfolio file doesn't load and the last good version is served, 0 once it loads again.",
+	})
+
+	ViewCache = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ssh_portfolio_view_cache_total",
+		Help: "Card renders served from the shared view cache, by result (hit, miss).",
+	}, []string{"result"})
+)
+
+// ObserveRender records how long a frame took since start. Use it as
+// `defer metrics.ObserveRender(time.Now())`.
+func ObserveRender(start time.Time) {
+	RenderSeconds.Observe(time.Since(start).Seconds())
+}
+
+// PortfolioLoaded counts a load attempt by its outcome, and keeps
+// PortfolioLoadFailing on the last one.
+func PortfolioLoaded(err error) {
+	if err != nil {
+		PortfolioLoads.WithLabelValues("failure").Inc()
+		PortfolioLoadFailing.Set(1)
+		return
+	}
+	PortfolioLoads.WithLabelValues("success").Inc()
+	PortfolioLoadFailing.Set(0)
+}
@@ -1,8 +1,11 @@
 package portfolio
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/Shbhom/ssh-portfolio/internal/avatar"
 	"gopkg.in/yaml.v3"
 )
 
@@ -11,11 +14,28 @@ func Load(path string) (*Portfolio, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parse(data, filepath.Dir(path))
+}
 
+// parse reads the YAML in data; dir is where the file lives, for the
+// paths inside it.
+func parse(data []byte, dir string) (*Portfolio, error) {
 	var p Portfolio
 	if err := yaml.Unmarshal(data, &p); err != nil {
 		return nil, err
 	}
 
+	if p.Avatar != "" {
+		path := p.Avatar
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		art, err := avatar.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("avatar: %w", err)
+		}
+		p.AvatarArt = art
+	}
+
 	return &p, nil
 }
@@ -1,5 +1,11 @@
 package portfolio
 
+import (
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/avatar"
+)
+
 type Overview struct {
 	Intro   string   `yaml:"intro"`
 	Bullets []string `yaml:"bullets"`
@@ -33,11 +39,27 @@ type Contact struct {
 	Phone    string `yaml:"phone"`
 }
 
+// Intro tunes the animation shown before the card. Every field is optional.
+type Intro struct {
+	Enabled *bool         `yaml:"enabled"` // defaults to true
+	Effect  string        `yaml:"effect"`  // typewriter (default), matrix, decrypt, figlet, fade or random
+	Tagline bool          `yaml:"tagline"` // type the tagline under the name
+	Tick    time.Duration `yaml:"tick"`    // time per frame, e.g. "60ms"
+	Blink   time.Duration `yaml:"blink"`   // cursor-only blinking before typing
+	Pause   time.Duration `yaml:"pause"`   // full text shown before the card
+	Color   string        `yaml:"color"`   // name color in the intro, e.g. "#FFD7FF"
+	Cursor  string        `yaml:"cursor"`  // cursor glyph, e.g. "▌"
+}
+
 type Portfolio struct {
 	Name        string       `yaml:"name"`
 	Tagline     string       `yaml:"tagline"`
+	Avatar      string       `yaml:"avatar"` // PNG or JPEG, relative to the YAML file
 	Overview    Overview     `yaml:"overview"`
 	Experiences []Experience `yaml:"experience"`
 	Projects    []Project    `yaml:"projects"`
 	Contact     Contact      `yaml:"contact"`
+	Intro       Intro        `yaml:"intro"`
+
+	AvatarArt *avatar.Avatar `yaml:"-"` // Avatar, shrunk at load time; nil without one
 }
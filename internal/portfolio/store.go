@@ -0,0 +1,77 @@
+package portfolio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot is one parsed version of the portfolio file. It is shared by
+// every session that started while it was current, so nothing may modify
+// it; a reload builds a new one instead.
+type Snapshot struct {
+	*Portfolio
+	Version uint64 // starts at 1, bumped whenever the file's content changes
+}
+
+// Store holds the current Snapshot of a portfolio file. Reads are a single
+// atomic load, so sessions never wait on a reload.
+type Store struct {
+	path string
+	cur  atomic.Pointer[Snapshot]
+
+	mu  sync.Mutex // serializes reloads
+	raw []byte     // file content last tried, so a broken file fails only once
+}
+
+// Open loads path into a new Store.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Current is the latest snapshot.
+func (s *Store) Current() *Snapshot {
+	return s.cur.Load()
+}
+
+// Path is the file the store reads.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Reload re-reads the file and swaps in a new snapshot if its content
+// changed. On error the current snapshot stays in place, and the same
+// broken content isn't reported again. Only the YAML is compared: a new
+// avatar image is picked up with the next change to the file.
+func (s *Store) Reload() (changed bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	old := s.cur.Load()
+	if old != nil && bytes.Equal(data, s.raw) {
+		return false, nil
+	}
+
+	s.raw = data
+	p, err := parse(data, filepath.Dir(s.path))
+	if err != nil {
+		return false, err
+	}
+
+	next := &Snapshot{Portfolio: p, Version: 1}
+	if old != nil {
+		next.Version = old.Version + 1
+	}
+	s.cur.Store(next)
+	return true, nil
+}
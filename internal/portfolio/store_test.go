@@ -0,0 +1,99 @@
+package portfolio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// copyFixture copies testdata/data.yaml and its avatar into a temporary
+// directory, so a test can edit the file.
+func copyFixture(t testing.TB) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"data.yaml", "avatar.png"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "data.yaml")
+}
+
+func TestStoreReload(t *testing.T) {
+	path := copyFixture(t)
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := s.Current()
+	if first.Version != 1 || first.Name != "Janet Doe" || first.AvatarArt == nil {
+		t.Fatalf("opened version %d, name %q, avatar %t", first.Version, first.Name, first.AvatarArt != nil)
+	}
+
+	if changed, err := s.Reload(); changed || err != nil {
+		t.Errorf("reloading an unchanged file = %t, %v", changed, err)
+	}
+
+	if err := os.WriteFile(path, []byte("name: [broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reload(); err == nil {
+		t.Error("reloading a broken file succeeded")
+	}
+	if _, err := s.Reload(); err != nil {
+		t.Errorf("the same broken file was reported twice: %v", err)
+	}
+	if s.Current() != first {
+		t.Error("a broken edit replaced the last good snapshot")
+	}
+
+	if err := os.WriteFile(path, []byte("name: Janet Fixed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := s.Reload(); !changed || err != nil {
+		t.Fatalf("reloading the fixed file = %t, %v", changed, err)
+	}
+	if cur := s.Current(); cur.Version != 2 || cur.Name != "Janet Fixed" {
+		t.Errorf("after the fix, version %d, name %q", cur.Version, cur.Name)
+	}
+	if first.Name != "Janet Doe" {
+		t.Error("the old snapshot changed under a session still using it")
+	}
+}
+
+// BenchmarkStoreCurrent is what a session pays for its portfolio now: one
+// atomic load of the shared snapshot.
+func BenchmarkStoreCurrent(b *testing.B) {
+	s, err := Open(copyFixture(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if s.Current() == nil {
+				b.Fatal("no snapshot")
+			}
+		}
+	})
+}
+
+// BenchmarkLoad is what every session paid when each one read and parsed
+// the file, avatar included, for itself.
+func BenchmarkLoad(b *testing.B) {
+	path := copyFixture(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := Load(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
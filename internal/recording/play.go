@@ -0,0 +1,81 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PlayOptions for Play.
+type PlayOptions struct {
+	Speed   float64       // 2 plays twice as fast, 0 means as recorded
+	MaxIdle time.Duration // longest pause between events, 0 = as recorded
+}
+
+// Play reads an asciicast v2 recording from r and writes its output to w
+// at the pace it was recorded, until it ends or ctx is done. Events other
+// than output, resizes included, are skipped: w is whatever terminal it
+// is played on.
+func Play(ctx context.Context, r io.Reader, w io.Writer, opts PlayOptions) error {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	// a decoder rather than a line scanner: a full-screen redraw can be
+	// one very long line
+	dec := json.NewDecoder(r)
+	var h Header
+	if err := dec.Decode(&h); err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	if h.Version != 2 {
+		return fmt.Errorf("asciicast version %d, only 2 is supported", h.Version)
+	}
+
+	var last float64 // recording time of the previous event
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for n := 1; ; n++ {
+		var ev []json.RawMessage
+		if err := dec.Decode(&ev); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("event %d: %w", n, err)
+		}
+		var t float64
+		var kind, data string
+		if len(ev) < 3 ||
+			json.Unmarshal(ev[0], &t) != nil ||
+			json.Unmarshal(ev[1], &kind) != nil ||
+			json.Unmarshal(ev[2], &data) != nil {
+			return fmt.Errorf("event %d: not [time, type, data]", n)
+		}
+		if kind != "o" {
+			continue
+		}
+
+		wait := time.Duration((t - last) * float64(time.Second))
+		if opts.MaxIdle > 0 {
+			wait = min(wait, opts.MaxIdle)
+		}
+		last = t
+		if wait = time.Duration(float64(wait) / speed); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+}
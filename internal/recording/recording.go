@@ -0,0 +1,267 @@
+// Package recording saves sessions as asciicast v2 files
+// (https://docs.asciinema.org/manual/asciicast/v2/), which asciinema, agg
+// and `ssh-portfolio replay` can play back.
+//
+// What the app draws is recorded, with resizes, but not the keystrokes
+// themselves. Text typed into the contact form, guestbook or shell is echoed
+// on screen, so it ends up in the recording all the same.
+package recording
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ext is what Dir names recordings, and all it prunes.
+const ext = ".cast"
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Options for Open.
+type Options struct {
+	All      bool          // record every session, not just those that ask
+	MaxAge   time.Duration // delete recordings older than this, 0 = keep
+	MaxBytes int64         // delete the oldest recordings past this total, 0 = no cap
+}
+
+// Dir is a directory of recordings. Retention is applied when it is
+// opened and whenever a recording starts, so it never holds much more than
+// Options allow.
+type Dir struct {
+	path string
+	opts Options
+
+	mu     sync.Mutex
+	active map[string]bool // file names still being written, never pruned
+}
+
+// Open uses path for recordings, creating it if needed.
+func Open(path string, opts Options) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, err
+	}
+	d := &Dir{path: path, opts: opts, active: make(map[string]bool)}
+	if err := d.Prune(time.Now()); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// All reports whether every session should be recorded.
+func (d *Dir) All() bool {
+	return d.opts.All
+}
+
+// Start creates a recording for a session as user, on a width x height
+// terminal of type term.
+func (d *Dir) Start(user, term string, width, height int, now time.Time) (*Cast, error) {
+	if err := d.Prune(now); err != nil {
+		log.Printf("failed to prune recordings: %v", err)
+	}
+
+	name := now.UTC().Format("20060102T150405Z") + "-" + fileSafe(user) + "-" + randomHex(3) + ext
+	f, err := os.OpenFile(filepath.Join(d.path, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	h := Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: now.Unix(),
+		Title:     user,
+	}
+	if term != "" {
+		h.Env = map[string]string{"TERM": term}
+	}
+	line, err := json.Marshal(h)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.active[name] = true
+	d.mu.Unlock()
+	return &Cast{
+		f:      f,
+		start:  now,
+		width:  width,
+		height: height,
+		done: func() {
+			d.mu.Lock()
+			delete(d.active, name)
+			d.mu.Unlock()
+		},
+	}, nil
+}
+
+// Prune deletes recordings past MaxAge, then the oldest ones until the rest
+// fit in MaxBytes.
+func (d *Dir) Prune(now time.Time) error {
+	if d.opts.MaxAge <= 0 && d.opts.MaxBytes <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return err
+	}
+
+	type file struct {
+		name string
+		mod  time.Time
+		size int64
+	}
+	var files []file
+	var total int64
+	d.mu.Lock()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ext) || d.active[e.Name()] {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // deleted under us
+		}
+		files = append(files, file{e.Name(), info.ModTime(), info.Size()})
+		total += info.Size()
+	}
+	d.mu.Unlock()
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+
+	for _, f := range files {
+		old := d.opts.MaxAge > 0 && now.Sub(f.mod) > d.opts.MaxAge
+		over := d.opts.MaxBytes > 0 && total > d.opts.MaxBytes
+		if !old && !over {
+			break // oldest first, so the rest are newer and fit
+		}
+		if err := os.Remove(filepath.Join(d.path, f.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= f.size
+	}
+	return nil
+}
+
+// Cast is one recording in progress. It is safe for concurrent use.
+type Cast struct {
+	mu            sync.Mutex
+	f             *os.File
+	start         time.Time
+	width, height int
+	partial       []byte // an incomplete UTF-8 sequence held back from the last write
+	failed        bool
+	done          func()
+}
+
+// Write records p as output. It never fails: a recording that can't be
+// written stops quietly rather than taking the session down with it.
+func (c *Cast) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// events are JSON strings, so a rune split across writes has to wait
+	// for the rest of it
+	data := append(c.partial, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	c.partial = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		c.event("o", string(data[:cut]))
+	}
+	return len(p), nil
+}
+
+// Resize records the terminal changing size. Sizes it already has are
+// ignored.
+func (c *Cast) Resize(width, height int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if width == c.width && height == c.height {
+		return
+	}
+	c.width, c.height = width, height
+	c.event("r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+// Close finishes the recording. Writes after Close are dropped.
+func (c *Cast) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return nil
+	}
+	if len(c.partial) > 0 {
+		c.event("o", string(c.partial))
+	}
+	err := c.f.Close()
+	c.f = nil
+	c.done()
+	return err
+}
+
+// event appends one event line. Callers hold mu.
+func (c *Cast) event(kind, data string) {
+	if c.f == nil || c.failed {
+		return
+	}
+	t := time.Since(c.start).Seconds()
+	line, _ := json.Marshal([]any{float64(int64(t*1e6)) / 1e6, kind, data})
+	if _, err := c.f.Write(append(line, '\n')); err != nil {
+		log.Printf("failed to write recording %s, stopping it: %v", c.f.Name(), err)
+		c.failed = true
+	}
+}
+
+// fileSafe keeps the letters, digits, dashes and underscores of s, for use
+// in a file name.
+func fileSafe(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '-' || r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			b.WriteRune(r)
+		}
+		if b.Len() == 32 {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "guest"
+	}
+	return b.String()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,180 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+)
+
+// node is a file or directory in the portfolio's filesystem.
+type node struct {
+	name     string
+	parent   *node
+	dir      bool
+	children []*node // directories, in the order the YAML lists things
+	content  string  // files
+	link     string  // what `open` copies, empty for nothing
+}
+
+func (n *node) add(child *node) *node {
+	child.parent = n
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// path is n's absolute path, with the root (the visitor's home) as "~".
+func (n *node) path() string {
+	if n.parent == nil {
+		return "~"
+	}
+	return n.parent.path() + "/" + n.name
+}
+
+// build lays the portfolio out as files:
+//
+//	~/about
+//	~/contact/{email,github,linkedin,phone}
+//	~/experience/<company>
+//	~/projects/<project>
+//
+// sep separates the details on an experience's second line.
+func build(p *portfolio.Portfolio, sep string) *node {
+	root := &node{dir: true}
+	root.add(&node{name: "about", content: about(p)})
+
+	contact := root.add(&node{name: "contact", dir: true})
+	for _, f := range []struct{ name, value, link string }{
+		{"email", p.Contact.Email, "mailto:" + p.Contact.Email},
+		{"github", p.Contact.GitHub, p.Contact.GitHub},
+		{"linkedin", p.Contact.LinkedIn, p.Contact.LinkedIn},
+		{"phone", p.Contact.Phone, ""},
+	} {
+		if f.value != "" {
+			contact.add(&node{name: f.name, content: f.value, link: f.link})
+		}
+	}
+
+	exps := root.add(&node{name: "experience", dir: true})
+	for _, e := range p.Experiences {
+		exps.add(&node{name: uniqueName(exps, slug(e.Company)), content: experience(e, sep)})
+	}
+
+	projs := root.add(&node{name: "projects", dir: true})
+	for _, pr := range p.Projects {
+		link := pr.Links.Code
+		if link == "" {
+			link = pr.Links.Demo
+		}
+		projs.add(&node{name: uniqueName(projs, slug(pr.Name)), content: project(pr), link: link})
+	}
+	return root
+}
+
+func about(p *portfolio.Portfolio) string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	if p.Tagline != "" {
+		b.WriteString("\n" + p.Tagline)
+	}
+	if intro := strings.TrimSpace(p.Overview.Intro); intro != "" {
+		b.WriteString("\n\n" + intro)
+	}
+	writeBullets(&b, p.Overview.Bullets)
+	return b.String()
+}
+
+func experience(e portfolio.Experience, sep string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s @ %s", e.Role, e.Company)
+	var meta []string
+	for _, s := range []string{e.Period, e.Location} {
+		if s != "" {
+			meta = append(meta, s)
+		}
+	}
+	if len(meta) > 0 {
+		b.WriteString("\n" + strings.Join(meta, " "+sep+" "))
+	}
+	writeBullets(&b, e.Bullets)
+	if s := strings.TrimSpace(e.Stack); s != "" {
+		b.WriteString("\n\nStack: " + s)
+	}
+	return b.String()
+}
+
+func project(p portfolio.Project) string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+	writeBullets(&b, p.Bullets)
+
+	var tail []string
+	if s := strings.TrimSpace(p.Stack); s != "" {
+		tail = append(tail, "Stack: "+s)
+	}
+	if p.Links.Code != "" {
+		tail = append(tail, "Code:  "+p.Links.Code)
+	}
+	if p.Links.Demo != "" {
+		tail = append(tail, "Demo:  "+p.Links.Demo)
+	}
+	if len(tail) > 0 {
+		b.WriteString("\n\n" + strings.Join(tail, "\n"))
+	}
+	return b.String()
+}
+
+func writeBullets(b *strings.Builder, bullets []string) {
+	first := true
+	for _, s := range bullets {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if first {
+			b.WriteString("\n")
+			first = false
+		}
+		b.WriteString("\n- " + s)
+	}
+}
+
+// slug makes a file name out of s: lower case, with runs of anything but
+// letters and digits turned into a single dash.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "untitled"
+	}
+	return b.String()
+}
+
+// uniqueName suffixes name with -2, -3... if dir already has it.
+func uniqueName(dir *node, name string) string {
+	unique := name
+	for i := 2; dir.child(unique) != nil; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	return unique
+}
@@ -0,0 +1,388 @@
+// Package shell is a make-believe shell for exploring the portfolio: a
+// read-only filesystem built from the YAML and a handful of commands to
+// walk it. Nothing here touches the real filesystem.
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+)
+
+// maxHistory is how many lines History keeps.
+const maxHistory = 100
+
+// Shell is one visitor's session: the filesystem, where they are in it and
+// what they typed.
+type Shell struct {
+	root, cwd *node
+	user      string
+	host      string
+
+	History []string // oldest first
+}
+
+// Result is what running a line produced.
+type Result struct {
+	Output string // possibly several lines, empty for none
+	Copy   string // `open` wants this on the visitor's clipboard
+	Clear  bool   // `clear` wants the scrollback wiped
+}
+
+type command struct {
+	usage string
+	help  string
+	run   func(s *Shell, args []string) Result
+}
+
+var commands map[string]command
+
+func init() {
+	// assigned here, since help refers back to the table
+	commands = map[string]command{
+		"help":   {"help", "show this list", (*Shell).help},
+		"whoami": {"whoami", "who this portfolio belongs to", (*Shell).whoami},
+		"ls":     {"ls [path...]", "list a directory", (*Shell).ls},
+		"cd":     {"cd [path]", "change directory (home without a path)", (*Shell).cd},
+		"pwd":    {"pwd", "print the current directory", (*Shell).pwd},
+		"cat":    {"cat path...", "print files", (*Shell).cat},
+		"grep":   {"grep term [path...]", "search files, ignoring case", (*Shell).grep},
+		"open":   {"open path|url", "copy a link to your clipboard", (*Shell).open},
+		"clear":  {"clear", "clear the screen", (*Shell).clear},
+	}
+}
+
+// New builds a shell over p for the visitor user, starting at home. sep
+// goes between details on one line, such as an experience's period and
+// location, so it can match the glyphs the rest of the card uses.
+func New(p *portfolio.Portfolio, user, sep string) *Shell {
+	root := build(p, sep)
+	host := "portfolio"
+	if p.Name != "" {
+		host = slug(p.Name)
+	}
+	if user == "" {
+		user = "guest"
+	}
+	return &Shell{root: root, cwd: root, user: user, host: host}
+}
+
+// Prompt is the bash-style prompt for the current directory.
+func (s *Shell) Prompt() string {
+	return fmt.Sprintf("%s@%s:%s$ ", s.user, s.host, s.cwd.path())
+}
+
+// Run runs one command line and records it in History.
+func (s *Shell) Run(line string) Result {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Result{}
+	}
+	if n := len(s.History); n == 0 || s.History[n-1] != line {
+		s.History = append(s.History, line)
+		if len(s.History) > maxHistory {
+			s.History = s.History[1:]
+		}
+	}
+
+	args := strings.Fields(line)
+	name, args := args[0], args[1:]
+	if cmd, ok := commands[name]; ok {
+		return cmd.run(s, args)
+	}
+	if name == "exit" || name == "logout" {
+		return Result{Output: "esc puts the prompt down, then q quits."}
+	}
+	return Result{Output: name + ": command not found (try help)"}
+}
+
+func (s *Shell) help([]string) Result {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	lines := []string{"Commands:"}
+	for _, name := range names {
+		c := commands[name]
+		lines = append(lines, fmt.Sprintf("  %-20s %s", c.usage, c.help))
+	}
+	lines = append(lines, "", "tab completes, up and down walk the history.")
+	return Result{Output: strings.Join(lines, "\n")}
+}
+
+func (s *Shell) whoami([]string) Result {
+	return Result{Output: s.root.child("about").content}
+}
+
+func (s *Shell) pwd([]string) Result {
+	return Result{Output: s.cwd.path()}
+}
+
+func (s *Shell) clear([]string) Result {
+	return Result{Clear: true}
+}
+
+func (s *Shell) ls(args []string) Result {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	var out []string
+	for _, arg := range args {
+		n, err := s.resolve(arg)
+		if err != nil {
+			out = append(out, "ls: "+arg+": "+err.Error())
+			continue
+		}
+		if len(args) > 1 {
+			out = append(out, arg+":")
+		}
+		if !n.dir {
+			out = append(out, n.name)
+			continue
+		}
+		var names []string
+		for _, c := range n.children {
+			names = append(names, displayName(c))
+		}
+		out = append(out, strings.Join(names, "  "))
+	}
+	return Result{Output: strings.Join(out, "\n")}
+}
+
+func (s *Shell) cd(args []string) Result {
+	if len(args) == 0 {
+		s.cwd = s.root
+		return Result{}
+	}
+	if len(args) > 1 {
+		return Result{Output: "cd: too many arguments"}
+	}
+	n, err := s.resolve(args[0])
+	switch {
+	case err != nil:
+		return Result{Output: "cd: " + args[0] + ": " + err.Error()}
+	case !n.dir:
+		return Result{Output: "cd: " + args[0] + ": " + errNotDir.Error()}
+	}
+	s.cwd = n
+	return Result{}
+}
+
+func (s *Shell) cat(args []string) Result {
+	if len(args) == 0 {
+		return Result{Output: "usage: cat path..."}
+	}
+	var out []string
+	for _, arg := range args {
+		n, err := s.resolve(arg)
+		switch {
+		case err != nil:
+			out = append(out, "cat: "+arg+": "+err.Error())
+		case n.dir:
+			out = append(out, "cat: "+arg+": Is a directory")
+		default:
+			out = append(out, n.content)
+		}
+	}
+	return Result{Output: strings.Join(out, "\n")}
+}
+
+// grep searches every file under the paths (the current directory by
+// default), like grep -ri.
+func (s *Shell) grep(args []string) Result {
+	if len(args) == 0 {
+		return Result{Output: "usage: grep term [path...]"}
+	}
+	term, paths := strings.ToLower(args[0]), args[1:]
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var out []string
+	for _, arg := range paths {
+		n, err := s.resolve(arg)
+		if err != nil {
+			out = append(out, "grep: "+arg+": "+err.Error())
+			continue
+		}
+		walk(n, func(f *node) {
+			for _, line := range strings.Split(f.content, "\n") {
+				if strings.Contains(strings.ToLower(line), term) {
+					out = append(out, s.relative(f)+": "+line)
+				}
+			}
+		})
+	}
+	if len(out) == 0 {
+		return Result{Output: "no matches for " + args[0]}
+	}
+	return Result{Output: strings.Join(out, "\n")}
+}
+
+// open copies a link: a URL as given, or the link of a file. Bare names
+// are also looked up in contact/ and projects/, so `open github` works from
+// anywhere.
+func (s *Shell) open(args []string) Result {
+	if len(args) != 1 {
+		return Result{Output: "usage: open path|url"}
+	}
+	arg := args[0]
+	if strings.Contains(arg, "://") || strings.HasPrefix(arg, "mailto:") {
+		return Result{Copy: arg}
+	}
+
+	n, err := s.resolve(arg)
+	if err != nil && !strings.Contains(arg, "/") {
+		for _, dir := range []string{"contact", "projects"} {
+			if c := s.root.child(dir).child(arg); c != nil {
+				n, err = c, nil
+				break
+			}
+		}
+	}
+	switch {
+	case err != nil:
+		return Result{Output: "open: " + arg + ": " + err.Error()}
+	case n.link == "":
+		return Result{Output: "open: " + arg + ": nothing to open"}
+	}
+	return Result{Copy: n.link}
+}
+
+// Complete completes the last word of line: a command name for the first
+// word, a path after that. It returns the new line and, when the word is
+// still ambiguous, the candidates.
+func (s *Shell) Complete(line string) (string, []string) {
+	i := strings.LastIndexByte(line, ' ')
+	head, word := line[:i+1], line[i+1:]
+
+	var names []string
+	var dirs map[string]bool
+	base := ""
+	if strings.TrimSpace(head) == "" {
+		for name := range commands {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	} else {
+		prefix := word
+		if j := strings.LastIndexByte(word, '/'); j >= 0 {
+			base, prefix = word[:j+1], word[j+1:]
+		}
+		dir, err := s.resolve(strings.TrimSuffix(base, "/"))
+		if base == "/" {
+			dir, err = s.root, nil
+		}
+		if err != nil || !dir.dir {
+			return line, nil
+		}
+		dirs = map[string]bool{}
+		for _, c := range dir.children {
+			names = append(names, base+c.name)
+			dirs[base+c.name] = c.dir
+		}
+		word = base + prefix
+	}
+
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, word) {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return line, nil
+	case 1:
+		if dirs[matches[0]] {
+			return head + matches[0] + "/", nil
+		}
+		return head + matches[0] + " ", nil
+	}
+
+	common := matches[0]
+	options := make([]string, len(matches))
+	for i, m := range matches {
+		for !strings.HasPrefix(m, common) {
+			// a whole rune at a time, so names that share only part
+			// of one, like "Éclair" and "Èvian", leave valid UTF-8
+			_, size := utf8.DecodeLastRuneInString(common)
+			common = common[:len(common)-size]
+		}
+		options[i] = strings.TrimPrefix(m, base)
+		if dirs[m] {
+			options[i] += "/"
+		}
+	}
+	return head + common, options
+}
+
+// resolve finds path relative to the current directory. "~" and a leading
+// "/" both mean home, the top of the filesystem.
+func (s *Shell) resolve(path string) (*node, error) {
+	n := s.cwd
+	switch {
+	case path == "" || path == ".":
+		return n, nil
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		n, path = s.root, strings.TrimPrefix(path[1:], "/")
+	case strings.HasPrefix(path, "/"):
+		n, path = s.root, path[1:]
+	}
+
+	for _, part := range strings.Split(path, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if n.parent != nil {
+				n = n.parent
+			}
+			continue
+		}
+		if !n.dir {
+			return nil, errNotDir
+		}
+		if n = n.child(part); n == nil {
+			return nil, errNotFound
+		}
+	}
+	return n, nil
+}
+
+// relative is n's path as seen from the current directory, for grep.
+func (s *Shell) relative(n *node) string {
+	cwd := s.cwd.path() + "/"
+	if p := n.path(); strings.HasPrefix(p, cwd) {
+		return p[len(cwd):]
+	}
+	return n.path()
+}
+
+func walk(n *node, file func(*node)) {
+	if !n.dir {
+		file(n)
+		return
+	}
+	for _, c := range n.children {
+		walk(c, file)
+	}
+}
+
+func displayName(n *node) string {
+	if n.dir {
+		return n.name + "/"
+	}
+	return n.name
+}
+
+var (
+	errNotFound = errors.New("No such file or directory")
+	errNotDir   = errors.New("Not a directory")
+)
@@ -0,0 +1,36 @@
+package shell
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+)
+
+func TestCompleteSplitRune(t *testing.T) {
+	// é and è share their first byte, but no whole rune
+	p := &portfolio.Portfolio{Projects: []portfolio.Project{{Name: "Éclair"}, {Name: "Èvian"}}}
+	s := New(p, "ada", "·")
+
+	line, options := s.Complete("cat projects/")
+	if !utf8.ValidString(line) {
+		t.Fatalf("completed to invalid UTF-8 %q", line)
+	}
+	if line != "cat projects/" {
+		t.Errorf("completed to %q, want no change", line)
+	}
+	if want := []string{"éclair", "èvian"}; !slices.Equal(options, want) {
+		t.Errorf("options = %q, want %q", options, want)
+	}
+}
+
+func TestExperienceSeparator(t *testing.T) {
+	p := &portfolio.Portfolio{Experiences: []portfolio.Experience{
+		{Role: "Engineer", Company: "Analytical", Period: "1843", Location: "London"},
+	}}
+	s := New(p, "ada", "|")
+	if got, want := s.Run("cat experience/analytical").Output, "Engineer @ Analytical\n1843 | London"; got != want {
+		t.Errorf("cat = %q, want %q", got, want)
+	}
+}
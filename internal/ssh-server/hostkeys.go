@@ -0,0 +1,119 @@
+package sshserver
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/ssh"
+)
+
+// hostKeys lists the host keys the server offers. ed25519 comes first since
+// it is what modern clients prefer; ecdsa and rsa are for older clients that
+// can't negotiate anything else.
+var hostKeys = []struct {
+	file string
+	opts []keygen.Option
+}{
+	{"ssh_host_ed25519", []keygen.Option{keygen.WithKeyType(keygen.Ed25519)}},
+	{"ssh_host_ecdsa", []keygen.Option{keygen.WithKeyType(keygen.ECDSA), keygen.WithEllipticCurve(elliptic.P256())}},
+	{"ssh_host_rsa", []keygen.Option{keygen.WithKeyType(keygen.RSA), keygen.WithBitSize(4096)}},
+}
+
+// Subdirectories of the key directory used while rotating.
+const (
+	nextKeyDir = "next" // staged keys, published but not served yet
+	oldKeyDir  = "old"  // retired keys, one timestamped directory per rotation
+)
+
+// HostKeyPaths returns the private key files the server reads from dir.
+func HostKeyPaths(dir string) []string {
+	paths := make([]string, 0, len(hostKeys))
+	for _, k := range hostKeys {
+		paths = append(paths, filepath.Join(dir, k.file))
+	}
+	return paths
+}
+
+// LoadHostKeys reads every host key in dir, generating the ones that are
+// missing.
+func LoadHostKeys(dir string) ([]*keygen.KeyPair, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var keys []*keygen.KeyPair
+	for _, k := range hostKeys {
+		path := filepath.Join(dir, k.file)
+		kp, err := keygen.New(path, append(k.opts, keygen.WithWrite())...)
+		if err != nil {
+			return nil, fmt.Errorf("host key %s: %w", path, err)
+		}
+		keys = append(keys, kp)
+	}
+	return keys, nil
+}
+
+// StagedHostKeys loads the keys waiting in dir/next, or returns nil if no
+// rotation is in progress.
+func StagedHostKeys(dir string) ([]*keygen.KeyPair, error) {
+	next := filepath.Join(dir, nextKeyDir)
+	if _, err := os.Stat(next); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return LoadHostKeys(next)
+}
+
+// RotateHostKeys starts a rotation by generating a fresh set of keys in
+// dir/next. The server keeps serving the current keys; publish both sets in
+// known_hosts / SSHFP for the grace period, then call PromoteHostKeys.
+//
+// An SSH server can only offer one key per algorithm, so "both active"
+// means both published: clients that have already learned the new keys
+// accept the server after promotion without a warning.
+func RotateHostKeys(dir string) ([]*keygen.KeyPair, error) {
+	next := filepath.Join(dir, nextKeyDir)
+	if _, err := os.Stat(next); err == nil {
+		return nil, fmt.Errorf("rotation already in progress, promote or remove %s first", next)
+	}
+	return LoadHostKeys(next)
+}
+
+// PromoteHostKeys finishes a rotation: the current keys move to
+// dir/old/<timestamp> and the staged keys replace them. The server picks
+// them up on its next restart.
+func PromoteHostKeys(dir string) error {
+	next := filepath.Join(dir, nextKeyDir)
+	if _, err := os.Stat(next); err != nil {
+		return fmt.Errorf("no staged keys in %s, run a rotation first", next)
+	}
+
+	old := filepath.Join(dir, oldKeyDir, time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.MkdirAll(old, 0o700); err != nil {
+		return err
+	}
+
+	for _, k := range hostKeys {
+		for _, name := range []string{k.file, k.file + ".pub"} {
+			if err := os.Rename(filepath.Join(dir, name), filepath.Join(old, name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err := os.Rename(filepath.Join(next, name), filepath.Join(dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Remove(next)
+}
+
+func withHostKeys(keys []*keygen.KeyPair) ssh.Option {
+	return func(srv *ssh.Server) error {
+		for _, k := range keys {
+			srv.AddHostKey(k.Signer())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package sshserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HTTPHandler serves Prometheus metrics and health probes for the server
+// built from cfg. It is meant for a side listener, not the public internet.
+func HTTPHandler(cfg Config) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	return mux
+}
+
+// ready reports whether a new session could be served right now: a
+// portfolio is loaded and the host keys are readable. It doesn't re-read
+// the portfolio: probes come often, and a broken edit leaves the last good
+// version in place, which ssh_portfolio_portfolio_load_failing reports.
+func ready(cfg Config) error {
+	if cfg.Portfolio.Current() == nil {
+		return errors.New("no portfolio loaded")
+	}
+	for _, path := range HostKeyPaths(cfg.HostKeyDir) {
+		if _, err := os.ReadFile(path); err != nil {
+			return fmt.Errorf("host key not readable: %w", err)
+		}
+	}
+	return nil
+}
+
+// withConnMetrics counts accepted connections and failed handshakes. It
+// wraps the callbacks set before it, so it must come after withConnLimit to
+// count the connections the limiter closes.
+func withConnMetrics() ssh.Option {
+	return func(srv *ssh.Server) error {
+		next := srv.ConnCallback
+		srv.ConnCallback = func(ctx ssh.Context, conn net.Conn) net.Conn {
+			metrics.Connections.Inc()
+			if next != nil {
+				return next(ctx, conn)
+			}
+			return conn
+		}
+		failed := srv.ConnectionFailedCallback
+		srv.ConnectionFailedCallback = func(conn net.Conn, err error) {
+			metrics.HandshakeFailures.Inc()
+			if failed != nil {
+				failed(conn, err)
+			}
+		}
+		return nil
+	}
+}
+
+func sessionMetricsMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			metrics.ActiveSessions.Inc()
+			defer metrics.ActiveSessions.Dec()
+			next(s)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sshserver
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+)
+
+func TestReadyz(t *testing.T) {
+	dir := t.TempDir()
+	path := writePortfolio(t, dir, testPortfolio)
+	store, err := portfolio.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Portfolio: store, HostKeyDir: hostKeyDir(t)}
+	h := HTTPHandler(cfg)
+
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+
+	if code := probe(); code != http.StatusOK {
+		t.Fatalf("/readyz = %d with a loaded portfolio", code)
+	}
+
+	// a broken edit leaves the last good version serving
+	writePortfolio(t, dir, "name: [broken\n")
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("reloading a broken file succeeded")
+	}
+	if code := probe(); code != http.StatusOK {
+		t.Errorf("/readyz = %d after a broken edit, but sessions are still served", code)
+	}
+
+	if err := os.Remove(filepath.Join(cfg.HostKeyDir, "ssh_host_ed25519")); err != nil {
+		t.Fatal(err)
+	}
+	if code := probe(); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz = %d without a host key", code)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("/debug/vars = %d, but there is nothing to show there", rec.Code)
+	}
+}
+
+// scrape reads one unlabelled metric from /metrics.
+func scrape(t *testing.T, h http.Handler, name string) float64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		if value, ok := strings.CutPrefix(sc.Text(), name+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	t.Fatalf("%s isn't in /metrics", name)
+	return 0
+}
+
+// TestConnMetrics checks that connections the rate limiter closes are
+// still counted.
+func TestConnMetrics(t *testing.T) {
+	addr := startServer(t, Config{Limits: Limits{ConnRate: 0.001, ConnBurst: 1}})
+	h := HTTPHandler(Config{})
+	before := scrape(t, h, "ssh_portfolio_connections_total")
+
+	for range 3 { // the first is let in, the others closed on accept
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := scrape(t, h, "ssh_portfolio_connections_total") - before
+		if got == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("counted %v of 3 connections", got)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
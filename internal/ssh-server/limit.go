@@ -0,0 +1,181 @@
+package sshserver
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// Limits caps how many visitors the server will serve at once. A zero value
+// for any field disables that particular limit.
+type Limits struct {
+	MaxSessions      int     // concurrent sessions across all visitors
+	MaxSessionsPerIP int     // concurrent sessions from one source IP
+	ConnRate         float64 // new connections per second allowed from one IP
+	ConnBurst        int     // how many new connections an IP may open back to back
+}
+
+const bucketGC = 1024 // prune idle buckets once we track this many IPs
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	cfg Limits
+
+	mu      sync.Mutex
+	active  int
+	perIP   map[string]int
+	buckets map[string]*bucket
+}
+
+func newLimiter(cfg Limits) *limiter {
+	return &limiter{
+		cfg:     cfg,
+		perIP:   make(map[string]int),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allowConn spends one of ip's tokens on a new connection, and reports
+// whether it had one.
+func (l *limiter) allowConn(ip string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.take(ip, now) {
+		metrics.LimitRejections.WithLabelValues("rate").Inc()
+		return false
+	}
+	return true
+}
+
+// acquire reserves a session slot for ip. It returns a non-empty reason when
+// the visitor is over one of the concurrency limits.
+func (l *limiter) acquire(ip string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cfg.MaxSessions > 0 && l.active >= l.cfg.MaxSessions {
+		metrics.LimitRejections.WithLabelValues("total").Inc()
+		return "total"
+	}
+	if l.cfg.MaxSessionsPerIP > 0 && l.perIP[ip] >= l.cfg.MaxSessionsPerIP {
+		metrics.LimitRejections.WithLabelValues("ip").Inc()
+		return "ip"
+	}
+
+	l.active++
+	l.perIP[ip]++
+	return ""
+}
+
+func (l *limiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	if l.perIP[ip] <= 1 {
+		delete(l.perIP, ip)
+	} else {
+		l.perIP[ip]--
+	}
+}
+
+// take spends one token from ip's bucket, refilling it first. Callers hold mu.
+func (l *limiter) take(ip string, now time.Time) bool {
+	if l.cfg.ConnRate <= 0 {
+		return true
+	}
+	burst := float64(max(l.cfg.ConnBurst, 1))
+
+	b, ok := l.buckets[ip]
+	if !ok {
+		if len(l.buckets) >= bucketGC {
+			l.prune(now, burst)
+		}
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[ip] = b
+	}
+
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*l.cfg.ConnRate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune drops buckets that have refilled completely; they carry no state a
+// fresh bucket wouldn't.
+func (l *limiter) prune(now time.Time, burst float64) {
+	for ip, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.cfg.ConnRate >= burst {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+func busyStyle(r *lipgloss.Renderer) lipgloss.Style {
+	return r.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.AdaptiveColor{Light: "#D7005F", Dark: "#FF5F87"}).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.AdaptiveColor{Light: "#767676", Dark: "#555555"}).
+		Padding(0, 2)
+}
+
+// withConnLimit closes connections from IPs over the connection rate as soon
+// as they are accepted, before the key exchange and host key signature they
+// would otherwise cost. There is no session yet to explain it on.
+func withConnLimit(l *limiter) ssh.Option {
+	return func(srv *ssh.Server) error {
+		next := srv.ConnCallback
+		srv.ConnCallback = func(ctx ssh.Context, conn net.Conn) net.Conn {
+			if !l.allowConn(remoteIP(conn.RemoteAddr()), time.Now()) {
+				return nil // closed by the server
+			}
+			if next != nil {
+				return next(ctx, conn)
+			}
+			return conn
+		}
+		return nil
+	}
+}
+
+// limitMiddleware turns visitors away with a short message once they are over
+// the concurrent session limits.
+func limitMiddleware(l *limiter) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			ip := remoteIP(s.RemoteAddr())
+			if reason := l.acquire(ip); reason != "" {
+				msg := "Too busy right now, try again in a minute."
+				out := busyStyle(newSessionRenderer(s, detectTermCaps(s))).Render(msg)
+				wish.Print(s, strings.ReplaceAll(out, "\n", "\r\n")+"\r\n")
+				_ = s.Exit(1)
+				return
+			}
+			defer l.release(ip)
+			next(s)
+		}
+	}
+}
+
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
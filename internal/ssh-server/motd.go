@@ -0,0 +1,85 @@
+package sshserver
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+)
+
+// Defaults for Config.Banner and Config.MOTD. Both are text/template strings
+// executed with a greeting.
+const (
+	DefaultBanner = "{{.Name}}{{if .Tagline}} - {{.Tagline}}{{end}}\n"
+	DefaultMOTD   = "Hi {{.User}}, welcome to {{.Name}}'s portfolio.\n" +
+		"{{if not .PTY}}It is an interactive app, so connect with a terminal (ssh -t) to look around.\n{{end}}" +
+		"{{if .Recording}}This session is being recorded.\n{{end}}"
+)
+
+// greeting is what banner and MOTD templates can refer to.
+type greeting struct {
+	User          string
+	ClientVersion string
+	Name          string
+	Tagline       string
+	PTY           bool // false before login and for non-interactive sessions
+	Recording     bool // the session will be recorded
+}
+
+func newGreeting(cfg Config, ctx ssh.Context) greeting {
+	g := greeting{User: ctx.User(), ClientVersion: ctx.ClientVersion()}
+	p := cfg.Portfolio.Current()
+	g.Name, g.Tagline = p.Name, p.Tagline
+	return g
+}
+
+func render(t *template.Template, g greeting) string {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, g); err != nil {
+		log.Printf("failed to render %s: %v", t.Name(), err)
+		return ""
+	}
+	return buf.String()
+}
+
+// withBanner sends the rendered banner before authentication. An empty
+// template sends none.
+func withBanner(cfg Config, t *template.Template) ssh.Option {
+	if cfg.Banner == "" {
+		return func(*ssh.Server) error { return nil }
+	}
+	return wish.WithBannerHandler(func(ctx ssh.Context) string {
+		return render(t, newGreeting(cfg, ctx))
+	})
+}
+
+// motdMiddleware prints the MOTD once the visitor is logged in. Sessions
+// without a PTY can't run the app, so for them the MOTD is all they get.
+func motdMiddleware(cfg Config, t *template.Template) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			_, _, isPty := s.Pty()
+
+			var motd string
+			if cfg.MOTD != "" {
+				g := newGreeting(cfg, s.Context())
+				g.PTY = isPty
+				g.Recording = isPty && records(cfg, s)
+				motd = render(t, g)
+			}
+
+			if !isPty {
+				wish.Print(s, motd)
+				_ = s.Exit(0)
+				return
+			}
+			if motd != "" {
+				wish.Print(s, strings.ReplaceAll(motd, "\n", "\r\n"))
+			}
+			next(s)
+		}
+	}
+}
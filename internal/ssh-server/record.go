@@ -0,0 +1,94 @@
+package sshserver
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/recording"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	wishtea "github.com/charmbracelet/wish/bubbletea"
+)
+
+// records reports whether s should be recorded: every session if the
+// server records them all, otherwise only visitors who ran
+// `ssh -t host record` or forwarded PORTFOLIO_RECORD.
+func records(cfg Config, s ssh.Session) bool {
+	if cfg.Recordings == nil {
+		return false
+	}
+	if cfg.Recordings.All() {
+		return true
+	}
+	for _, arg := range s.Command() {
+		if arg == "record" {
+			return true
+		}
+	}
+	v := getenv(s.Environ(), "PORTFOLIO_RECORD")
+	return v != "" && v != "0"
+}
+
+// startRecording returns the program options that tee everything the app
+// draws into a new recording, with a filter that records resizes. It
+// returns none if the recording can't be started; the session goes on
+// unrecorded.
+func startRecording(cfg Config, s ssh.Session) []tea.ProgramOption {
+	pty, _, _ := s.Pty()
+	if pty.Slave == nil {
+		return nil
+	}
+	cast, err := cfg.Recordings.Start(s.User(), pty.Term, pty.Window.Width, pty.Window.Height, time.Now())
+	if err != nil {
+		log.Printf("failed to start recording: %v", err)
+		return nil
+	}
+	go func() {
+		<-s.Context().Done()
+		if err := cast.Close(); err != nil {
+			log.Printf("failed to finish recording: %v", err)
+		}
+	}()
+
+	return []tea.ProgramOption{
+		tea.WithOutput(teeFile{pty.Slave, cast}),
+		tea.WithFilter(func(_ tea.Model, msg tea.Msg) tea.Msg {
+			if size, ok := msg.(tea.WindowSizeMsg); ok {
+				cast.Resize(size.Width, size.Height)
+			}
+			return msg
+		}),
+	}
+}
+
+// teeFile is the PTY with every write copied to a recording. It keeps the
+// PTY's Fd, so bubbletea still sees a terminal it can size. It doesn't embed
+// the *os.File: its WriteString would get around Write.
+type teeFile struct {
+	pty  *os.File
+	cast *recording.Cast
+}
+
+func (t teeFile) Read(p []byte) (int, error) { return t.pty.Read(p) }
+func (t teeFile) Close() error               { return t.pty.Close() }
+func (t teeFile) Fd() uintptr                { return t.pty.Fd() }
+
+func (t teeFile) Write(p []byte) (int, error) {
+	n, err := t.pty.Write(p)
+	t.cast.Write(p[:n])
+	return n, err
+}
+
+// programHandler runs h's program with exactly the options h returns.
+// wishtea's own handler appends its input and output options after them,
+// which would send the output around a teeFile.
+func programHandler(h wishtea.Handler) wishtea.ProgramHandler {
+	return func(s ssh.Session) *tea.Program {
+		m, opts := h(s)
+		if m == nil {
+			return nil
+		}
+		return tea.NewProgram(m, opts...)
+	}
+}
@@ -0,0 +1,100 @@
+package sshserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	wishtea "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// resizePoll is how often teaMiddleware checks the PTY for a size change
+// it wasn't told about.
+const resizePoll = 200 * time.Millisecond
+
+// teaMiddleware runs the program h builds, like wishtea's middleware, but
+// doesn't lose window changes. With a real PTY the ssh package resizes it
+// from the same channel of window changes, so each change goes either to
+// it or to us: the ones we get are applied to the PTY and passed on, and
+// for the others we watch the PTY's size.
+func teaMiddleware(h wishtea.ProgramHandler) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			p := h(s)
+			if p == nil {
+				next(s)
+				return
+			}
+			pty, windowChanges, ok := s.Pty()
+			if !ok {
+				wish.Fatalln(s, "no active terminal, skipping")
+				return
+			}
+
+			ctx, cancel := context.WithCancel(s.Context())
+			go forwardResizes(ctx, p, pty, windowChanges)
+			if _, err := p.Run(); err != nil {
+				log.Printf("app exited with error: %v", err)
+			}
+			// restores the terminal if the program crashed
+			p.Kill()
+			cancel()
+			next(s)
+		}
+	}
+}
+
+// forwardResizes sends p a WindowSizeMsg whenever the visitor's window
+// changes size, until ctx is done.
+func forwardResizes(ctx context.Context, p *tea.Program, pty ssh.Pty, windowChanges <-chan ssh.Window) {
+	last := pty.Window
+	resized := func(w, h int) {
+		if w != last.Width || h != last.Height {
+			last.Width, last.Height = w, h
+			p.Send(tea.WindowSizeMsg{Width: w, Height: h})
+		}
+	}
+
+	poll := time.NewTicker(resizePoll)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.Quit()
+			return
+		case win, ok := <-windowChanges:
+			if !ok {
+				windowChanges = nil // closed with the session, wait for ctx
+				continue
+			}
+			if pty.Slave != nil {
+				_ = pty.Resize(win.Width, win.Height)
+			}
+			resized(win.Width, win.Height)
+		case <-poll.C:
+			if w, h, ok := ptySize(pty); ok {
+				resized(w, h)
+			}
+		}
+	}
+}
+
+// ptySize reads the PTY's size. It goes through SyscallConn rather than Fd,
+// which races with the ssh package closing the PTY when the session ends.
+func ptySize(pty ssh.Pty) (w, h int, ok bool) {
+	if pty.Slave == nil {
+		return 0, 0, false
+	}
+	conn, err := pty.Slave.SyscallConn()
+	if err != nil {
+		return 0, 0, false
+	}
+	err = conn.Control(func(fd uintptr) {
+		w, h, err = term.GetSize(fd)
+	})
+	return w, h, err == nil
+}
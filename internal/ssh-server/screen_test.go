@@ -0,0 +1,317 @@
+package sshserver
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// screen is a small VT emulator, just enough of one to replay what
+// bubbletea draws: printing with auto-wrap, CR/LF, cursor movement, erasing,
+// and the alternate screen. Colors and other attributes are dropped, and
+// sequences it doesn't know are skipped, so tests can check what a visitor
+// would read at each position. Like a real terminal it answers the
+// background color and cursor position queries the renderer sends; without
+// a reply those read the next keys typed.
+type screen struct {
+	mu      sync.Mutex
+	w, h    int
+	cells   [][]string // "" is the right half of a wide character
+	x, y    int        // x == w means the next character wraps
+	main    [][]string // the main screen while the alternate one is up
+	pending []byte     // an incomplete sequence or character from the last Write
+
+	reply   io.Writer // where answers to queries go, the session's input
+	answers []byte    // answers to send once the screen is unlocked
+}
+
+func newScreen(w, h int) *screen {
+	s := &screen{w: w, h: h}
+	s.cells = blankCells(w, h)
+	return s
+}
+
+func blankCells(w, h int) [][]string {
+	cells := make([][]string, h)
+	for y := range cells {
+		cells[y] = blankRow(w)
+	}
+	return cells
+}
+
+func blankRow(w int) []string {
+	row := make([]string, w)
+	for x := range row {
+		row[x] = " "
+	}
+	return row
+}
+
+// resize changes the size, keeping what fits, like a terminal window being
+// dragged.
+func (s *screen) resize(w, h int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cells := blankCells(w, h)
+	for y := 0; y < min(h, s.h); y++ {
+		copy(cells[y], s.cells[y][:min(w, s.w)])
+	}
+	s.cells, s.w, s.h = cells, w, h
+	s.x, s.y = min(s.x, w-1), min(s.y, h-1)
+}
+
+// String is the screen's text, one line per row, trailing blanks trimmed.
+func (s *screen) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := make([]string, s.h)
+	for y, row := range s.cells {
+		lines[y] = strings.TrimRight(strings.Join(row, ""), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Write feeds the emulator terminal output.
+func (s *screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	b := append(s.pending, p...)
+	for len(b) > 0 {
+		n := s.step(b)
+		if n == 0 { // incomplete, wait for the rest
+			break
+		}
+		b = b[n:]
+	}
+	s.pending = append([]byte(nil), b...)
+	answers := s.answers
+	s.answers = nil
+	s.mu.Unlock()
+
+	if len(answers) > 0 && s.reply != nil {
+		if _, err := s.reply.Write(answers); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// step handles the character or sequence at the start of b and returns
+// how many bytes it took, or 0 if b ends before it does.
+func (s *screen) step(b []byte) int {
+	switch c := b[0]; {
+	case c == ansi.ESC:
+		return s.escape(b)
+	case c == '\r':
+		s.x = 0
+	case c == '\n':
+		s.lineFeed()
+	case c == '\b':
+		s.x = max(min(s.x, s.w-1)-1, 0)
+	case c == '\t':
+		s.x = min((s.x/8+1)*8, s.w-1)
+	case c < 0x20 || c == 0x7f:
+		// other controls, the bell included, draw nothing
+	default:
+		if !utf8.FullRune(b) {
+			return 0
+		}
+		r, n := utf8.DecodeRune(b)
+		s.print(string(r))
+		return n
+	}
+	return 1
+}
+
+func (s *screen) lineFeed() {
+	if s.y < s.h-1 {
+		s.y++
+		return
+	}
+	copy(s.cells, s.cells[1:])
+	s.cells[s.h-1] = blankRow(s.w)
+}
+
+func (s *screen) print(ch string) {
+	width := ansi.StringWidth(ch)
+	if width == 0 {
+		// a combining mark joins the character before it
+		if x := min(s.x, s.w) - 1; x >= 0 {
+			s.cells[s.y][x] += ch
+		}
+		return
+	}
+	if s.x+width > s.w {
+		s.x = 0
+		s.lineFeed()
+	}
+	s.cells[s.y][s.x] = ch
+	if width == 2 && s.x+1 < s.w {
+		s.cells[s.y][s.x+1] = ""
+	}
+	s.x += width
+}
+
+// escape handles an escape sequence: CSI, OSC and DCS are parsed, other
+// two-byte sequences are skipped.
+func (s *screen) escape(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+	switch b[1] {
+	case '[':
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				s.csi(string(b[2:i]), b[i])
+				return i + 1
+			}
+		}
+		return 0
+	case ']', 'P', '_', '^':
+		// string sequences end with BEL or ST
+		for i := 2; i < len(b); i++ {
+			if b[i] == ansi.BEL {
+				s.osc(b[1], string(b[2:i]))
+				return i + 1
+			}
+			if b[i] == ansi.ESC && i+1 < len(b) && b[i+1] == '\\' {
+				s.osc(b[1], string(b[2:i]))
+				return i + 2
+			}
+		}
+		return 0
+	}
+	return 2
+}
+
+// osc answers color queries for a white-on-black terminal.
+func (s *screen) osc(kind byte, body string) {
+	switch {
+	case kind != ']':
+	case body == "10;?":
+		s.answers = append(s.answers, "\x1b]10;rgb:ffff/ffff/ffff\x1b\\"...)
+	case body == "11;?":
+		s.answers = append(s.answers, "\x1b]11;rgb:0000/0000/0000\x1b\\"...)
+	}
+}
+
+func (s *screen) csi(params string, final byte) {
+	if strings.HasPrefix(params, "?") {
+		if params == "?1049" || params == "?47" || params == "?1047" {
+			s.altScreen(final == 'h')
+		}
+		return
+	}
+	args := csiArgs(params)
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+	s.x = min(s.x, s.w-1) // any movement ends a pending wrap
+
+	switch final {
+	case 'n': // device status report
+		if params == "6" {
+			s.answers = fmt.Appendf(s.answers, "\x1b[%d;%dR", s.y+1, s.x+1)
+		}
+	case 'c': // primary device attributes: a VT220
+		if params == "" || params == "0" {
+			s.answers = append(s.answers, "\x1b[?62;22c"...)
+		}
+	case 'A':
+		s.y = max(s.y-arg(0, 1), 0)
+	case 'B':
+		s.y = min(s.y+arg(0, 1), s.h-1)
+	case 'C':
+		s.x = min(s.x+arg(0, 1), s.w-1)
+	case 'D':
+		s.x = max(s.x-arg(0, 1), 0)
+	case 'E':
+		s.x, s.y = 0, min(s.y+arg(0, 1), s.h-1)
+	case 'F':
+		s.x, s.y = 0, max(s.y-arg(0, 1), 0)
+	case 'G':
+		s.x = min(arg(0, 1), s.w) - 1
+	case 'd':
+		s.y = min(arg(0, 1), s.h) - 1
+	case 'H', 'f':
+		s.y, s.x = min(arg(0, 1), s.h)-1, min(arg(1, 1), s.w)-1
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseRow(s.y, s.x, s.w)
+			for y := s.y + 1; y < s.h; y++ {
+				s.eraseRow(y, 0, s.w)
+			}
+		case 1:
+			for y := 0; y < s.y; y++ {
+				s.eraseRow(y, 0, s.w)
+			}
+			s.eraseRow(s.y, 0, s.x+1)
+		default:
+			s.cells = blankCells(s.w, s.h)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseRow(s.y, s.x, s.w)
+		case 1:
+			s.eraseRow(s.y, 0, s.x+1)
+		default:
+			s.eraseRow(s.y, 0, s.w)
+		}
+	case 'L':
+		for range arg(0, 1) {
+			copy(s.cells[s.y+1:], s.cells[s.y:s.h-1])
+			s.cells[s.y] = blankRow(s.w)
+		}
+	case 'M':
+		for range arg(0, 1) {
+			copy(s.cells[s.y:], s.cells[s.y+1:])
+			s.cells[s.h-1] = blankRow(s.w)
+		}
+	}
+	// m (colors), r (scroll margins) and the rest don't change the text
+}
+
+func (s *screen) eraseRow(y, from, to int) {
+	for x := from; x < min(to, s.w); x++ {
+		s.cells[y][x] = " "
+	}
+}
+
+func (s *screen) altScreen(on bool) {
+	switch {
+	case on && s.main == nil:
+		s.main = s.cells
+		s.cells = blankCells(s.w, s.h)
+	case !on && s.main != nil:
+		s.cells, s.main = s.main, nil
+		if len(s.cells) != s.h || len(s.cells[0]) != s.w {
+			old := s.cells
+			s.cells = blankCells(s.w, s.h)
+			for y := 0; y < min(len(old), s.h); y++ {
+				copy(s.cells[y], old[y][:min(len(old[y]), s.w)])
+			}
+		}
+	}
+}
+
+// csiArgs parses "1;2" into [1 2]; missing numbers are 0.
+func csiArgs(params string) []int {
+	if params == "" {
+		return nil
+	}
+	parts := strings.Split(params, ";")
+	args := make([]int, len(parts))
+	for i, p := range parts {
+		args[i], _ = strconv.Atoi(p)
+	}
+	return args
+}
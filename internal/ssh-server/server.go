@@ -1,9 +1,15 @@
 package sshserver
 
 import (
-	"path"
+	"io"
+	"text/template"
+	"time"
 
+	"github.com/Shbhom/ssh-portfolio/internal/analytics"
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
 	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	"github.com/Shbhom/ssh-portfolio/internal/recording"
 	"github.com/Shbhom/ssh-portfolio/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/ssh"
@@ -12,14 +18,59 @@ import (
 	"github.com/charmbracelet/wish/logging"
 )
 
-func New(addr, hostKeyPath string) (*ssh.Server, error) {
+// Config holds everything New needs to build the server.
+type Config struct {
+	Addr       string
+	HostKeyDir string           // ed25519, ecdsa and rsa host keys, generated if missing
+	Portfolio  *portfolio.Store // every session starts from its current snapshot
+	Limits     Limits
+
+	IdleTimeout time.Duration // disconnect after this long without input
+	MaxTimeout  time.Duration // disconnect this long after connecting
+
+	Events analytics.Sink // visitor analytics, nil disables them
+
+	Guestbook *guestbook.Book // nil hides the Guestbook tab
+	Contact   *contact.Queue  // nil hides the contact form
+
+	Recordings *recording.Dir // where sessions are recorded, nil records none
+
+	Banner string // pre-auth banner template, empty for none
+	MOTD   string // post-login message template, the only output for non-PTY sessions
+}
+
+func New(cfg Config) (*ssh.Server, error) {
+	keys, err := LoadHostKeys(cfg.HostKeyDir)
+	if err != nil {
+		return nil, err
+	}
+
+	banner, err := template.New("banner").Parse(cfg.Banner)
+	if err != nil {
+		return nil, err
+	}
+	motd, err := template.New("motd").Parse(cfg.MOTD)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := newLimiter(cfg.Limits)
 	srv, err := wish.NewServer(
-		wish.WithAddress(addr),
-		wish.WithHostKeyPath(hostKeyPath),
+		wish.WithAddress(cfg.Addr),
+		withHostKeys(keys),
+		wish.WithIdleTimeout(cfg.IdleTimeout),
+		wish.WithMaxTimeout(cfg.MaxTimeout),
 		ssh.AllocatePty(),
+		withVisitorAuth(),
+		withConnLimit(limits),
+		withConnMetrics(), // after withConnLimit, so it counts what the limiter closes
+		withBanner(cfg, banner),
 		wish.WithMiddleware(
 			logging.Middleware(),
-			wishtea.Middleware(teaHandler),
+			teaMiddleware(programHandler(teaHandler(cfg))),
+			motdMiddleware(cfg, motd),
+			sessionMetricsMiddleware(),
+			limitMiddleware(limits), // last = outermost, runs first
 		),
 	)
 	if err != nil {
@@ -28,19 +79,55 @@ func New(addr, hostKeyPath string) (*ssh.Server, error) {
 	return srv, nil
 }
 
-func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func teaHandler(cfg Config) wishtea.Handler {
+	seen := newVisitors()
+	cards := ui.NewViewCache()
 
-	p, err := portfolio.Load(path.Join("internal/config/data.yaml"))
-	if err != nil {
-		panic(err)
-	}
-	m := ui.NewModel(s.User(), p)
+	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 
-	opts := []tea.ProgramOption{
-		tea.WithInput(s),
-		tea.WithOutput(s),
-		tea.WithAltScreen(), // optional but nice
-	}
+		pty, _, _ := s.Pty()
+		caps := detectTermCaps(s)
+
+		// OSC 52 goes to the same PTY the program draws to; writes to it
+		// are serialized, so it can't tear a frame
+		var clipboard io.Writer
+		if caps.clipboard && pty.Slave != nil {
+			clipboard = pty.Slave
+		}
+		ip := remoteIP(s.RemoteAddr())
+		recorded := records(cfg, s)
+		m := ui.NewModel(s.User(), cfg.Portfolio.Current(), ui.Options{
+			IdleTimeout: cfg.IdleTimeout,
+			MaxTimeout:  cfg.MaxTimeout,
+			Events:      analytics.NewRecorder(cfg.Events, ip),
+			Session: ui.SessionInfo{
+				ClientVersion: s.Context().ClientVersion(),
+				Term:          pty.Term,
+				Width:         pty.Window.Width,
+				Height:        pty.Window.Height,
+			},
+			Renderer: newSessionRenderer(s, caps),
+			ASCII:    !caps.utf8,
+
+			Accessible: caps.accessible,
+			SkipIntro:  seen.visit(ip, time.Now()),
 
-	return m, opts
+			Guestbook:      cfg.Guestbook,
+			KeyFingerprint: keyFingerprint(s),
+			Contact:        cfg.Contact,
+			RemoteIP:       ip,
+			Clipboard:      clipboard,
+			Recording:      recorded,
+			Cards:          cards,
+		})
+
+		opts := append(wishtea.MakeOptions(s),
+			tea.WithAltScreen(), // optional but nice
+		)
+		if recorded {
+			opts = append(opts, startRecording(cfg, s)...)
+		}
+
+		return m, opts
+	}
 }
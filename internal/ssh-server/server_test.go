@@ -0,0 +1,453 @@
+package sshserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const testPortfolio = `name: "Jane Tester"
+tagline: "Backend Engineer"
+intro:
+  enabled: false
+overview:
+  intro: "I write servers and the tests for them."
+experience:
+  - company: "Acme Corp"
+    role: "Engineer"
+    period: "2020 – now"
+    bullets:
+      - "Kept the lights on"
+projects:
+  - name: "Logbook"
+    bullets:
+      - "Structured log search"
+contact:
+  email: "jane@example.com"
+`
+
+// sharedKeys holds one set of generated host keys for the whole run:
+// a 4096-bit RSA key takes long enough to make every test wait on it.
+var sharedKeys struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if sharedKeys.dir != "" {
+		os.RemoveAll(sharedKeys.dir)
+	}
+	os.Exit(code)
+}
+
+// hostKeyDir is a fresh t.TempDir with the shared host keys copied in.
+func hostKeyDir(t *testing.T) string {
+	t.Helper()
+	sharedKeys.once.Do(func() {
+		sharedKeys.dir, sharedKeys.err = os.MkdirTemp("", "ssh-portfolio-keys")
+		if sharedKeys.err == nil {
+			_, sharedKeys.err = LoadHostKeys(sharedKeys.dir)
+		}
+	})
+	if sharedKeys.err != nil {
+		t.Fatalf("generating host keys: %v", sharedKeys.err)
+	}
+
+	dir := t.TempDir()
+	entries, err := os.ReadDir(sharedKeys.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(sharedKeys.dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// writePortfolio writes data.yaml into dir and returns its path.
+func writePortfolio(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "data.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startServer serves cfg on a free port on 127.0.0.1 until the test ends,
+// and returns the address. cfg gets a fresh host key directory, a
+// portfolio store if it has none, and the default MOTD.
+func startServer(t *testing.T, cfg Config) string {
+	t.Helper()
+	cfg.HostKeyDir = hostKeyDir(t)
+	if cfg.Portfolio == nil {
+		store, err := portfolio.Open(writePortfolio(t, t.TempDir(), testPortfolio))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Portfolio = store
+	}
+	if cfg.MOTD == "" {
+		cfg.MOTD = DefaultMOTD
+	}
+
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+	return ln.Addr().String()
+}
+
+// dial logs in as user with a throwaway ed25519 key.
+func dial(t *testing.T, addr, user string) *gossh.Client {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            user,
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// terminal is an interactive session drawn onto a screen.
+type terminal struct {
+	t       *testing.T
+	session *gossh.Session
+	stdin   io.Writer
+	screen  *screen
+	done    chan error // the session's exit
+}
+
+// openTerminal starts the app in a w x h PTY.
+func openTerminal(t *testing.T, client *gossh.Client, w, h int) *terminal {
+	t.Helper()
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := session.RequestPty("xterm-256color", h, w, gossh.TerminalModes{}); err != nil {
+		t.Fatalf("pty: %v", err)
+	}
+	term := &terminal{t: t, session: session, screen: newScreen(w, h), done: make(chan error, 1)}
+	session.Stdout = term.screen
+	if term.stdin, err = session.StdinPipe(); err != nil {
+		t.Fatal(err)
+	}
+	term.screen.reply = term.stdin
+	if err := session.Shell(); err != nil {
+		t.Fatalf("shell: %v", err)
+	}
+	go func() { term.done <- session.Wait() }()
+	t.Cleanup(func() { session.Close() })
+	return term
+}
+
+// waitForCard waits until the app has drawn the card. The MOTD before it
+// already shows the name, and keys typed before the app reads its input can
+// be lost.
+func (term *terminal) waitForCard() {
+	term.t.Helper()
+	term.waitFor("q: quit")
+}
+
+// waitFor waits until the screen shows want.
+func (term *terminal) waitFor(want string) {
+	term.t.Helper()
+	if err := term.poll(func(s string) bool { return strings.Contains(s, want) }); err != nil {
+		term.t.Fatalf("waiting for %q: %v", want, err)
+	}
+}
+
+func (term *terminal) poll(ok func(string) bool) error {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s := term.screen.String()
+		if ok(s) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out, screen:\n%s", s)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func (term *terminal) press(keys string) {
+	term.t.Helper()
+	if _, err := io.WriteString(term.stdin, keys); err != nil {
+		term.t.Fatalf("typing %q: %v", keys, err)
+	}
+}
+
+// wait waits for the session to end.
+func (term *terminal) wait() error {
+	term.t.Helper()
+	select {
+	case err := <-term.done:
+		return err
+	case <-time.After(5 * time.Second):
+		term.t.Fatalf("session still open, screen:\n%s", term.screen.String())
+		return nil
+	}
+}
+
+// findRow returns the row and column where want starts, or -1, -1.
+func findRow(screen, want string) (int, int) {
+	for y, line := range strings.Split(screen, "\n") {
+		if i := strings.Index(line, want); i >= 0 {
+			return y, len([]rune(line[:i]))
+		}
+	}
+	return -1, -1
+}
+
+func TestPTYSession(t *testing.T) {
+	addr := startServer(t, Config{})
+	term := openTerminal(t, dial(t, addr, "tester"), 120, 40)
+
+	term.waitForCard()
+	screen := term.screen.String()
+	for _, want := range []string{"Jane Tester", "Backend Engineer", "I write servers and the tests for them.",
+		"Overview", "Experience", "Projects", "Contact"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("card is missing %q:\n%s", want, screen)
+		}
+	}
+
+	term.press("2")
+	term.waitFor("Acme Corp")
+	term.press("3")
+	term.waitFor("Structured log search")
+	term.press("4")
+	term.waitFor("Let's Work Together")
+
+	term.press("q")
+	if err := term.wait(); err != nil {
+		t.Errorf("session ended with %v", err)
+	}
+	// the alternate screen is gone, what's left is the MOTD
+	if got := term.screen.String(); !strings.Contains(got, "Hi tester, welcome to Jane Tester's portfolio.") {
+		t.Errorf("after quitting, screen is:\n%s", got)
+	}
+}
+
+func TestNonPTYSession(t *testing.T) {
+	addr := startServer(t, Config{})
+	session, err := dial(t, addr, "script").NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	out, err := session.CombinedOutput("")
+	if err != nil {
+		t.Fatalf("session ended with %v, output %q", err, out)
+	}
+	want := "Hi script, welcome to Jane Tester's portfolio.\n" +
+		"It is an interactive app, so connect with a terminal (ssh -t) to look around.\n"
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestResize(t *testing.T) {
+	addr := startServer(t, Config{})
+	term := openTerminal(t, dial(t, addr, "tester"), 140, 40)
+	term.waitForCard()
+
+	// centered: as many columns left of the card as right of it
+	centered := func(width int) func(string) bool {
+		return func(s string) bool {
+			y, left := findRow(s, "┌")
+			if y < 0 {
+				return false
+			}
+			_, right := findRow(s, "┐")
+			return right >= 0 && left-(width-1-right) >= -1 && left-(width-1-right) <= 1
+		}
+	}
+	if err := term.poll(centered(140)); err != nil {
+		t.Fatalf("card not centered at 140 columns: %v", err)
+	}
+	_, before := findRow(term.screen.String(), "┌")
+
+	term.screen.resize(110, 36)
+	if err := term.session.WindowChange(36, 110); err != nil {
+		t.Fatal(err)
+	}
+	if err := term.poll(func(s string) bool {
+		_, left := findRow(s, "┌")
+		return left != before && centered(110)(s)
+	}); err != nil {
+		t.Fatalf("card not re-centered at 110 columns: %v", err)
+	}
+	if y, _ := findRow(term.screen.String(), "Jane Tester"); y < 0 {
+		t.Errorf("name gone after resize:\n%s", term.screen.String())
+	}
+}
+
+func TestQuitKeys(t *testing.T) {
+	addr := startServer(t, Config{})
+	client := dial(t, addr, "tester")
+	for _, key := range []string{"q", "\x1b", "\x03"} { // q, esc, ctrl+c
+		t.Run(fmt.Sprintf("%q", key), func(t *testing.T) {
+			term := openTerminal(t, client, 120, 40)
+			term.waitForCard()
+			term.press(key)
+			if err := term.wait(); err != nil {
+				t.Errorf("session ended with %v", err)
+			}
+		})
+	}
+}
+
+func TestConcurrentClients(t *testing.T) {
+	addr := startServer(t, Config{})
+
+	const clients = 8
+	// tabs that change the screen: keys sent back to back arrive as one
+	tabs := []struct{ key, want string }{
+		{"2", "Acme Corp"},
+		{"3", "Structured log search"},
+		{"4", "Let's Work Together"},
+	}
+	terms := make([]*terminal, clients)
+	for i := range terms {
+		terms[i] = openTerminal(t, dial(t, addr, fmt.Sprintf("visitor%d", i)), 120, 40)
+	}
+
+	var wg sync.WaitGroup
+	for i, term := range terms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tab := tabs[i%len(tabs)]
+			check := func(want string) bool {
+				if err := term.poll(func(s string) bool { return strings.Contains(s, want) }); err != nil {
+					t.Errorf("client %d waiting for %q: %v", i, want, err)
+					return false
+				}
+				return true
+			}
+			if !check("q: quit") { // the card, see waitForCard
+				return
+			}
+			io.WriteString(term.stdin, tab.key)
+			if !check(tab.want) {
+				return
+			}
+			io.WriteString(term.stdin, "q")
+			select {
+			case err := <-term.done:
+				if err != nil {
+					t.Errorf("client %d: session ended with %v", i, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("client %d: session still open, screen:\n%s", i, term.screen.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMalformedPortfolio(t *testing.T) {
+	const broken = "name: \"Jane Tester\nexperience: [\n"
+
+	if _, err := portfolio.Open(writePortfolio(t, t.TempDir(), broken)); err == nil {
+		t.Fatal("opening a malformed data.yaml succeeded")
+	}
+
+	// a running server keeps serving the last good file through a broken edit
+	dir := t.TempDir()
+	store, err := portfolio.Open(writePortfolio(t, dir, testPortfolio))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := startServer(t, Config{Portfolio: store})
+	client := dial(t, addr, "tester")
+
+	writePortfolio(t, dir, broken)
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("reloading a malformed data.yaml succeeded")
+	}
+	term := openTerminal(t, client, 120, 40)
+	term.waitForCard()
+	if y, _ := findRow(term.screen.String(), "Jane Tester"); y < 0 {
+		t.Errorf("the last good portfolio isn't served:\n%s", term.screen.String())
+	}
+	term.press("q")
+	term.wait()
+
+	writePortfolio(t, dir, strings.Replace(testPortfolio, "Jane Tester", "Janet Fixed", 1))
+	if _, err := store.Reload(); err != nil {
+		t.Fatalf("reloading the fixed file: %v", err)
+	}
+	term = openTerminal(t, client, 120, 40)
+	term.waitForCard()
+	if y, _ := findRow(term.screen.String(), "Janet Fixed"); y < 0 {
+		t.Errorf("the fixed portfolio isn't served:\n%s", term.screen.String())
+	}
+}
+
+// TestLoginMethods checks that every way a client may be limited to gets
+// in, while a key is still asked for first.
+func TestLoginMethods(t *testing.T) {
+	addr := startServer(t, Config{})
+	for name, auth := range map[string]gossh.AuthMethod{
+		"password": gossh.Password("anything"),
+		"keyboard-interactive": gossh.KeyboardInteractive(
+			func(string, string, []string, []bool) ([]string, error) { return nil, nil },
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+				User:            "tester",
+				Auth:            []gossh.AuthMethod{auth},
+				HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+				Timeout:         5 * time.Second,
+			})
+			if err != nil {
+				t.Fatalf("login with %s: %v", name, err)
+			}
+			client.Close()
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package sshserver
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	wishtea "github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// Terminals that only do monochrome / 7-bit output. Anything here gets the
+// ASCII glyph set and no colors.
+var plainTerms = map[string]bool{
+	"dumb":       true,
+	"vt52":       true,
+	"vt100":      true,
+	"vt102":      true,
+	"vt220":      true,
+	"vt320":      true,
+	"xterm-mono": true,
+}
+
+// termCaps is what we could work out about the visitor's terminal from
+// TERM, COLORTERM and the locale variables their client forwarded.
+type termCaps struct {
+	profile    termenv.Profile
+	utf8       bool
+	clipboard  bool // may understand OSC 52
+	accessible bool // visitor asked for the screen-reader-friendly layout
+}
+
+func detectTermCaps(s ssh.Session) termCaps {
+	pty, _, _ := s.Pty()
+	env := s.Environ()
+	return termCaps{
+		profile: colorProfile(pty.Term, getenv(env, "COLORTERM")),
+		utf8:    supportsUTF8(pty.Term, env),
+
+		clipboard: supportsClipboard(pty.Term),
+
+		accessible: wantsAccessible(s.Command(), env),
+	}
+}
+
+// wantsAccessible reports whether the visitor asked for accessible mode by
+// running `ssh -t host a11y`, forwarding PORTFOLIO_A11Y, or forwarding
+// NO_COLOR (https://no-color.org).
+func wantsAccessible(cmd, env []string) bool {
+	for _, arg := range cmd {
+		if arg == "a11y" {
+			return true
+		}
+	}
+	if v := getenv(env, "PORTFOLIO_A11Y"); v != "" && v != "0" {
+		return true
+	}
+	return getenv(env, "NO_COLOR") != ""
+}
+
+// colorProfile maps TERM/COLORTERM to how many colors we may use. Hex colors
+// in the styles are downsampled to this by the renderer.
+func colorProfile(term, colorterm string) termenv.Profile {
+	term = strings.ToLower(term)
+	colorterm = strings.ToLower(colorterm)
+
+	switch {
+	case term == "" || plainTerms[term] || strings.HasSuffix(term, "-m"):
+		return termenv.Ascii
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return termenv.TrueColor
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	default:
+		// xterm, linux, screen, cygwin, Windows consoles...: assume the
+		// 16 colors everything supports.
+		return termenv.ANSI
+	}
+}
+
+// supportsUTF8 follows the usual locale precedence (LC_ALL, LC_CTYPE, LANG).
+// Most clients forward at least LANG; if none is set we assume UTF-8 unless
+// TERM says otherwise.
+func supportsUTF8(term string, env []string) bool {
+	if plainTerms[strings.ToLower(term)] {
+		return false
+	}
+	for _, k := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := getenv(env, k); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return true
+}
+
+// supportsClipboard rules out terminals that certainly can't set the
+// clipboard with OSC 52: plain VTs and the Linux console. Whether any other
+// terminal allows it is up to its settings, which we can't see.
+func supportsClipboard(term string) bool {
+	term = strings.ToLower(term)
+	return term != "" && term != "linux" && !plainTerms[term]
+}
+
+// newSessionRenderer builds the renderer every style in the session is made
+// from. wish's renderer writes to the visitor's PTY and asks their terminal
+// for its background color; we only correct the color profile, since termenv
+// doesn't look at COLORTERM or know about plain VTs.
+func newSessionRenderer(s ssh.Session, caps termCaps) *lipgloss.Renderer {
+	r := wishtea.MakeRenderer(s)
+	r.SetColorProfile(caps.profile)
+	return r
+}
+
+func getenv(env []string, key string) string {
+	for _, kv := range env {
+		if v, ok := strings.CutPrefix(kv, key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
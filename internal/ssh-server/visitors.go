@@ -0,0 +1,53 @@
+package sshserver
+
+import (
+	"sync"
+	"time"
+)
+
+// returningAfter is how long a visitor has to have been away before they see
+// the intro again.
+const (
+	returningAfter = 24 * time.Hour
+	maxVisitors    = 10000
+)
+
+// visitors remembers who connected recently so returning visitors can skip
+// the intro. It lives in memory only; a restart makes everyone new again.
+type visitors struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newVisitors() *visitors {
+	return &visitors{seen: make(map[string]time.Time)}
+}
+
+// visit records ip and reports whether it was already seen within
+// returningAfter.
+func (v *visitors) visit(ip string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	last, ok := v.seen[ip]
+	returning := ok && now.Sub(last) < returningAfter
+
+	if !ok && len(v.seen) >= maxVisitors {
+		v.forget(now)
+	}
+	v.seen[ip] = now
+	return returning
+}
+
+// forget drops visitors who are no longer "returning" anyway. Callers hold mu.
+func (v *visitors) forget(now time.Time) {
+	for ip, last := range v.seen {
+		if now.Sub(last) >= returningAfter {
+			delete(v.seen, ip)
+		}
+	}
+	if len(v.seen) >= maxVisitors {
+		// everyone is recent; start over rather than grow without bound
+		clear(v.seen)
+	}
+}
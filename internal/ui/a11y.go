@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// setAccessible switches the linear, screen-reader-friendly layout on or off.
+// It skips the intro and drops color entirely, since screen readers gain
+// nothing from either.
+func (m model) setAccessible(on bool) model {
+	m.accessible = on
+	if on {
+		m.loading = false
+		m.renderer.SetColorProfile(termenv.Ascii)
+	} else {
+		m.renderer.SetColorProfile(m.colorProfile)
+	}
+	m.expList = paintDots(m.expList, m.styles, m.glyphs)
+	m.projList = paintDots(m.projList, m.styles, m.glyphs)
+	return m
+}
+
+// viewAccessible renders the current tab as plain headed text: no border,
+// no centering, no decorative glyphs, and page position spelled out.
+func (m model) viewAccessible() string {
+	var lines []string
+	add := func(s ...string) { lines = append(lines, s...) }
+
+	switch m.activeTab {
+	case overviewTab:
+		m.a11yOverview(add)
+	case experienceTab:
+		m.a11yExperience(add)
+	case projectsTab:
+		m.a11yProjects(add)
+	case contactTab:
+		m.a11yContact(add)
+	case guestbookTab:
+		m.a11yGuestbook(add)
+	case shellTab:
+		m.a11yShell(add)
+	}
+
+	add("")
+	if m.timeoutWarning != "" {
+		add(m.timeoutWarning, "")
+	}
+	if m.toast != "" {
+		add(m.toast, "")
+	}
+	if m.recording {
+		add("This session is being recorded.", "")
+	}
+
+	var tabs []string
+	for _, tab := range m.tabs() {
+		label := tabLabels[tab]
+		if tab == m.activeTab {
+			label += " (current)"
+		}
+		tabs = append(tabs, label)
+	}
+	add("Tabs: "+strings.Join(tabs, ", ")+".",
+		fmt.Sprintf("Keys: h and l switch tabs, 1 to %d jump to a tab, j and k change page, a leaves accessible mode, q quits.", len(tabs)))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (m model) a11yOverview(add func(...string)) {
+	p := m.portfolio
+	add("Overview", "")
+	if p.Name != "" {
+		add(p.Name)
+	}
+	if p.Tagline != "" {
+		add(p.Tagline)
+	}
+	if intro := strings.TrimSpace(p.Overview.Intro); intro != "" {
+		add("", intro)
+	}
+	a11yBullets(add, p.Overview.Bullets)
+}
+
+func (m model) a11yExperience(add func(...string)) {
+	exps := m.portfolio.Experiences
+	if len(exps) == 0 {
+		add("Experience", "", "No experience listed.")
+		return
+	}
+	idx := min(max(m.expList.Page, 0), len(exps)-1)
+	exp := exps[idx]
+
+	add(fmt.Sprintf("Experience %d of %d", idx+1, len(exps)), "")
+	add(fmt.Sprintf("%s, %s", exp.Role, exp.Company))
+	if exp.Period != "" {
+		add("Period: " + exp.Period)
+	}
+	if exp.Location != "" {
+		add("Location: " + exp.Location)
+	}
+	a11yBullets(add, exp.Bullets)
+	if s := strings.TrimSpace(exp.Stack); s != "" {
+		add("", "Stack: "+s)
+	}
+}
+
+func (m model) a11yProjects(add func(...string)) {
+	projs := m.portfolio.Projects
+	if len(projs) == 0 {
+		add("Projects", "", "No projects listed.")
+		return
+	}
+	idx := min(max(m.projList.Page, 0), len(projs)-1)
+	proj := projs[idx]
+
+	add(fmt.Sprintf("Project %d of %d", idx+1, len(projs)), "", proj.Name)
+	a11yBullets(add, proj.Bullets)
+	if s := strings.TrimSpace(proj.Stack); s != "" {
+		add("", "Stack: "+s)
+	}
+	if len(m.copyItems()) > 0 {
+		add("")
+		m.a11yCopyItems(add)
+		add(a11yQRHint("tab", len(m.copyItems())))
+	}
+}
+
+func (m model) a11yContact(add func(...string)) {
+	if m.form.open {
+		m.a11yContactForm(add)
+		return
+	}
+
+	add("Contact", "")
+	m.a11yCopyItems(add)
+	if n := len(m.copyItems()); n > 0 {
+		add("", a11yQRHint("j and k", n))
+	}
+	if m.contact != nil {
+		add("")
+		if m.form.status != "" {
+			add(m.form.status)
+		}
+		add("Press m to send a message from here.")
+	}
+}
+
+// a11yCopyItems lists the tab's copyable items, marking the selected one.
+func (m model) a11yCopyItems(add func(...string)) {
+	for i, item := range m.copyItems() {
+		line := item.label + ": " + item.value
+		if i == m.copySel {
+			line += " (selected)"
+		}
+		add(line)
+	}
+}
+
+func a11yBullets(add func(...string), bullets []string) {
+	first := true
+	for _, b := range bullets {
+		if b = strings.TrimSpace(b); b == "" {
+			continue
+		}
+		if first {
+			add("")
+			first = false
+		}
+		add("- " + b)
+	}
+}
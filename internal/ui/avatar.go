@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/Shbhom/ssh-portfolio/internal/avatar"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const avatarGap = 3 // columns between the avatar and the name
+
+// viewAvatar draws the largest avatar size that fits in cols x rows, or ""
+// if there is none, none fits or the terminal can't show one. Colors go
+// through the session's renderer, so they come out at whatever depth the
+// terminal has; without color the avatar is drawn in braille instead.
+func (m model) viewAvatar(cols, rows int) string {
+	art := m.portfolio.AvatarArt
+	if art == nil || m.ascii {
+		return ""
+	}
+	var pic *avatar.Picture
+	for _, p := range art.Sizes {
+		if p.Cols <= cols && p.Rows <= rows {
+			pic = p
+		}
+	}
+	if pic == nil {
+		return ""
+	}
+
+	if m.renderer.ColorProfile() == termenv.Ascii {
+		return m.brailleAvatar(pic)
+	}
+	return m.halfBlockAvatar(pic)
+}
+
+// halfBlockAvatar draws two pixels per cell: the top one as the foreground
+// of "▀", the bottom one as its background.
+func (m model) halfBlockAvatar(pic *avatar.Picture) string {
+	rows := make([]string, pic.Rows)
+	var b strings.Builder
+	for y := range pic.Rows {
+		b.Reset()
+		for x := range pic.Cols {
+			top, bottom := pic.Half(x, 2*y), pic.Half(x, 2*y+1)
+			switch {
+			case opaque(top) && opaque(bottom):
+				b.WriteString(m.renderer.NewStyle().Foreground(hexColor(top)).Background(hexColor(bottom)).Render("▀"))
+			case opaque(top):
+				b.WriteString(m.renderer.NewStyle().Foreground(hexColor(top)).Render("▀"))
+			case opaque(bottom):
+				b.WriteString(m.renderer.NewStyle().Foreground(hexColor(bottom)).Render("▄"))
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return strings.Join(rows, "\n")
+}
+
+// brailleDots maps a pixel's place in a 2x4 braille cell to its dot.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleAvatar draws 2x4 pixels per cell as braille dots in the
+// terminal's own foreground: a dot for each pixel brighter than average on
+// a dark background, darker than average on a light one.
+func (m model) brailleAvatar(pic *avatar.Picture) string {
+	w, h := pic.Cols*2, pic.Rows*4
+
+	var sum float64
+	n := 0
+	for y := range h {
+		for x := range w {
+			if c := pic.Dot(x, y); opaque(c) {
+				sum += luma(c)
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return ""
+	}
+	mean := sum / float64(n)
+	dark := m.renderer.HasDarkBackground()
+
+	rows := make([]string, pic.Rows)
+	var b strings.Builder
+	for cy := range pic.Rows {
+		b.Reset()
+		for cx := range pic.Cols {
+			r := rune(0x2800)
+			for dy := range 4 {
+				for dx := range 2 {
+					c := pic.Dot(cx*2+dx, cy*4+dy)
+					if opaque(c) && (luma(c) > mean) == dark {
+						r |= brailleDots[dy][dx]
+					}
+				}
+			}
+			b.WriteRune(r)
+		}
+		rows[cy] = b.String()
+	}
+	return strings.Join(rows, "\n")
+}
+
+func opaque(c color.NRGBA) bool {
+	return c.A >= 0x80
+}
+
+func luma(c color.NRGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
+
+func hexColor(c color.NRGBA) lipgloss.Color {
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
+	"github.com/muesli/termenv"
+)
+
+// viewKey is everything the rendered card depends on. Two sessions with
+// the same key get byte-identical frames, so they can share one.
+type viewKey struct {
+	version       uint64 // portfolio snapshot
+	tab, page     int    // page within the tab, 0 where there is none
+	sel           int    // selected copy item
+	width, height int
+	profile       termenv.Profile
+	dark          bool // palette
+	ascii         bool // glyph set
+	recording     bool // footer indicator
+	guestbook     bool // adds a tab to the row
+	contact       bool // the Contact tab offers the form
+}
+
+// ViewCache is a small LRU of rendered cards, shared by a server's
+// sessions. Frames only change on a keypress or resize, while a session may
+// redraw many times in between, and most visitors look at the same few
+// pages. Snapshot versions are only unique within one portfolio.Store, so
+// sessions on different stores mustn't share a cache.
+type ViewCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // front = most recently used
+	entries map[viewKey]*list.Element
+}
+
+type cachedView struct {
+	key  viewKey
+	view string
+}
+
+const viewCacheSize = 512
+
+// NewViewCache returns an empty cache for a server's sessions to share.
+func NewViewCache() *ViewCache {
+	return newViewCache(viewCacheSize)
+}
+
+func newViewCache(max int) *ViewCache {
+	return &ViewCache{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[viewKey]*list.Element),
+	}
+}
+
+// get returns the view for key, calling render on a miss. render runs
+// outside the lock, so a slow frame doesn't hold up other sessions; two
+// sessions missing the same key at once both render it.
+func (c *ViewCache) get(key viewKey, render func() string) string {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.mu.Unlock()
+		metrics.ViewCache.WithLabelValues("hit").Inc()
+		return el.Value.(*cachedView).view
+	}
+	c.mu.Unlock()
+	metrics.ViewCache.WithLabelValues("miss").Inc()
+
+	view := render()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(&cachedView{key: key, view: view})
+		for c.order.Len() > c.max {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*cachedView).key)
+		}
+	}
+	return view
+}
+
+// cardKey describes the card m would draw right now.
+func (m model) cardKey() viewKey {
+	k := viewKey{
+		version: m.portfolio.Version,
+		tab:     m.activeTab,
+		width:   m.width,
+		height:  m.height,
+		profile: m.renderer.ColorProfile(),
+		dark:    m.renderer.HasDarkBackground(),
+		ascii:   m.ascii,
+		sel:     m.copySel,
+
+		recording: m.recording,
+		guestbook: m.guestbook != nil,
+		contact:   m.contact != nil,
+	}
+	switch m.activeTab {
+	case experienceTab:
+		k.page = m.expList.Page
+	case projectsTab:
+		k.page = m.projList.Page
+	}
+	return k
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestViewCacheLRU(t *testing.T) {
+	c := newViewCache(2)
+	renders := 0
+	get := func(tab int) string {
+		return c.get(viewKey{tab: tab}, func() string {
+			renders++
+			return "tab " + strconv.Itoa(tab)
+		})
+	}
+
+	get(1)
+	get(2)
+	if got := get(1); got != "tab 1" || renders != 2 {
+		t.Fatalf("get(1) = %q after %d renders, want a hit", got, renders)
+	}
+	get(3) // evicts 2, the least recently used
+	if get(1); renders != 3 {
+		t.Errorf("1 was evicted, though it was used more recently than 2")
+	}
+	if get(2); renders != 4 {
+		t.Errorf("2 is still cached after a third key came in")
+	}
+}
+
+// TestViewCacheShared checks that sessions sharing a cache only share
+// frames when their cards would match.
+func TestViewCacheShared(t *testing.T) {
+	p := loadFixture(t, "full")
+	book, err := guestbook.Open(filepath.Join(t.TempDir(), "guestbook.log"), guestbook.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer book.Close()
+	q, err := contact.NewQueue(t.TempDir(), nil, contact.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared := NewViewCache()
+	for _, tc := range []struct {
+		name string
+		opts Options
+	}{
+		{"guestbook", Options{Guestbook: book}},
+		{"contact", Options{Contact: q}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			view := func(opts Options, cards *ViewCache) string {
+				opts.SkipIntro, opts.Cards = true, cards
+				m := newTestModel(t, p, opts)
+				return drive(t, m, 120, 40, press("4")...).View()
+			}
+			without := view(Options{}, shared)
+			with := view(tc.opts, shared)
+			if with == without {
+				t.Fatal("got the card cached for a session without it")
+			}
+			if want := view(tc.opts, nil); with != want {
+				t.Errorf("shared cache served\n%s\nwant\n%s", with, want)
+			}
+		})
+	}
+}
+
+// sizedModel is a session on the full fixture with its window size set,
+// on the Experience tab.
+func sizedModel(b *testing.B) model {
+	m := newTestModel(b, loadFixture(b, "full"), Options{SkipIntro: true})
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	next, _ = next.(model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	return next.(model)
+}
+
+// BenchmarkViewCached is a redraw served from the view cache.
+func BenchmarkViewCached(b *testing.B) {
+	m := sizedModel(b)
+	m.View()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		m.View()
+	}
+}
+
+// BenchmarkViewUncached renders the same card from scratch every time.
+func BenchmarkViewUncached(b *testing.B) {
+	m := sizedModel(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		m.viewCard()
+	}
+}
+
+// BenchmarkViewCachedParallel is many sessions on the same page redrawing
+// at once, all contending for the cache's lock.
+func BenchmarkViewCachedParallel(b *testing.B) {
+	m := sizedModel(b)
+	m.View()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.View()
+		}
+	})
+}
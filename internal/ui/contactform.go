@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	contactTab = 3
+
+	maxContactsPerSession = 3 // the queue is cheap, the owner's inbox isn't
+)
+
+// contactForm is the "send me a message" form on the Contact tab.
+type contactForm struct {
+	fields []textinput.Model // name, reply-to, message
+	focus  int
+	open   bool
+	status string // outcome of the last send, or why it was refused
+	sent   int
+}
+
+func newContactForm(r *lipgloss.Renderer, st styles) contactForm {
+	field := func(label, placeholder string, limit int) textinput.Model {
+		in := textinput.New()
+		in.Prompt = fmt.Sprintf("%-9s> ", label)
+		in.Placeholder = placeholder
+		in.CharLimit = limit
+		in.Width = appWidth - 20
+
+		// see newGuestbookInput
+		in.PromptStyle = st.meta
+		in.TextStyle = r.NewStyle()
+		in.PlaceholderStyle = st.meta
+		in.Cursor.Style = st.cursor
+		in.Cursor.TextStyle = r.NewStyle()
+		in.Cursor.SetMode(cursor.CursorStatic)
+		return in
+	}
+	return contactForm{fields: []textinput.Model{
+		field("Name", "Ada Lovelace", contact.MaxName),
+		field("Email", "you@example.com", contact.MaxReplyTo),
+		field("Message", "What would you like to talk about?", contact.MaxBody),
+	}}
+}
+
+// openContactForm shows the form, if the server can deliver messages.
+func (m model) openContactForm() (model, tea.Cmd) {
+	if m.form.sent >= maxContactsPerSession {
+		m.form.status = "Thanks, that's plenty for one visit!"
+		return m, nil
+	}
+	m.form.open = true
+	m.form.status = ""
+	return m.focusContactField(0)
+}
+
+func (m model) focusContactField(i int) (model, tea.Cmd) {
+	m.form.fields[m.form.focus].Blur()
+	m.form.focus = i
+	return m, m.form.fields[i].Focus()
+}
+
+// updateContactForm handles a key while the form is open.
+func (m model) updateContactForm(msg tea.KeyMsg, now time.Time) (model, tea.Cmd) {
+	last := len(m.form.fields) - 1
+
+	switch msg.String() {
+	case "esc":
+		m.form.fields[m.form.focus].Blur()
+		m.form.open = false
+		m.form.status = ""
+		return m, nil
+	case "tab", "down":
+		return m.focusContactField(min(m.form.focus+1, last))
+	case "shift+tab", "up":
+		return m.focusContactField(max(m.form.focus-1, 0))
+	case "enter":
+		if m.form.focus < last {
+			return m.focusContactField(m.form.focus + 1)
+		}
+		return m.sendContactForm(now), nil
+	}
+
+	var cmd tea.Cmd
+	m.form.fields[m.form.focus], cmd = m.form.fields[m.form.focus].Update(msg)
+	return m, cmd
+}
+
+func (m model) sendContactForm(now time.Time) model {
+	err := m.contact.Enqueue(contact.Message{
+		Time:        now,
+		Name:        m.form.fields[0].Value(),
+		ReplyTo:     m.form.fields[1].Value(),
+		Body:        m.form.fields[2].Value(),
+		User:        m.username,
+		Fingerprint: m.fingerprint,
+		IP:          m.remoteIP,
+	})
+	if err != nil {
+		m.form.status = "Not sent: " + err.Error()
+		return m
+	}
+
+	m.form.sent++
+	for i := range m.form.fields {
+		m.form.fields[i].Reset()
+		m.form.fields[i].Blur()
+	}
+	m.form.focus = 0
+	m.form.open = false
+	m.form.status = "Thanks! Your message is on its way."
+	return m
+}
+
+func (m model) viewContactForm() string {
+	lines := []string{
+		m.centerInContent(m.styles.contactTitle.Render("Send me a message")),
+	}
+	for _, f := range m.form.fields {
+		lines = append(lines, f.View())
+	}
+	lines = append(lines, "")
+
+	if m.form.status != "" {
+		lines = append(lines, m.form.status)
+	}
+	g := m.glyphs
+	lines = append(lines, m.styles.meta.Render(fmt.Sprintf(
+		"tab/%s: next field  %s  enter: send (from the last field)  %s  esc: cancel",
+		g.down, g.bullet, g.bullet)))
+	return strings.Join(lines, "\n")
+}
+
+func (m model) a11yContactForm(add func(...string)) {
+	add("Send me a message", "")
+	for i, label := range []string{"Name", "Email", "Message"} {
+		line := label + ": " + m.form.fields[i].Value()
+		if i == m.form.focus {
+			line += " (editing)"
+		}
+		add(line)
+	}
+	add("")
+	if m.form.status != "" {
+		add(m.form.status)
+	}
+	add("Press tab for the next field, enter on the message to send, escape to cancel.")
+}
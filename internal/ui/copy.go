@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"encoding/base64"
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const toastFor = 4 * time.Second
+
+// copyItem is a value on the card the visitor can copy: selecting text in
+// an alt-screen app over SSH is fiddly, and not every terminal follows
+// termLink's OSC 8 links.
+type copyItem struct {
+	label string // "Email", "Code", ...
+	value string // what lands on the clipboard
+	link  string // termLink target, empty to show the value as plain text
+	qr    string // what the QR code holds, if not link or value
+}
+
+// view is how the item is drawn on the card.
+func (c copyItem) view() string {
+	if c.link == "" {
+		return c.value
+	}
+	return termLink(c.label, c.link)
+}
+
+// toastMsg clears the toast it was scheduled for, unless a newer one has
+// replaced it since.
+type toastMsg int
+
+// copyItems is what can be copied on the current tab, in the order it is
+// drawn.
+func (m model) copyItems() []copyItem {
+	var items []copyItem
+	add := func(item copyItem) {
+		if item.value != "" {
+			items = append(items, item)
+		}
+	}
+
+	switch m.activeTab {
+	case projectsTab:
+		if projs := m.portfolio.Projects; len(projs) > 0 {
+			proj := projs[min(max(m.projList.Page, 0), len(projs)-1)]
+			add(copyItem{label: "Code", value: proj.Links.Code, link: proj.Links.Code})
+			add(copyItem{label: "Demo", value: proj.Links.Demo, link: proj.Links.Demo})
+		}
+	case contactTab:
+		c := m.portfolio.Contact
+		add(copyItem{label: "GitHub", value: c.GitHub, link: c.GitHub})
+		add(copyItem{label: "LinkedIn", value: c.LinkedIn, link: c.LinkedIn})
+		add(copyItem{label: "Email", value: c.Email, link: "mailto:" + c.Email})
+		add(copyItem{label: "Phone", value: c.Phone, qr: telURI(c.Phone)}) // plain text, no link
+	}
+	return items
+}
+
+// moveCopySel moves the selection by delta, wrapping around.
+func (m model) moveCopySel(delta int) model {
+	if n := len(m.copyItems()); n > 0 {
+		m.copySel = ((m.copySel+delta)%n + n) % n
+	}
+	return m
+}
+
+// copySelected puts the selected item on the visitor's clipboard with
+// OSC 52, and says so in a toast. Without a clipboard, or a terminal known
+// to lack OSC 52, the toast shows the value instead.
+func (m model) copySelected() (model, tea.Cmd) {
+	items := m.copyItems()
+	if len(items) == 0 {
+		return m, nil
+	}
+	item := items[min(m.copySel, len(items)-1)]
+
+	if m.clipboard == nil {
+		return m.showToast("Your terminal can't take clipboard data. " + item.label + ": " + item.value)
+	}
+	m, clear := m.showToast("Copied " + item.label + " to your clipboard. Nothing there? Your terminal may not allow OSC 52.")
+	return m, tea.Batch(writeClipboard(m.clipboard, item.value), clear)
+}
+
+func (m model) showToast(text string) (model, tea.Cmd) {
+	m.toastSeq++
+	m.toast = text
+	seq := m.toastSeq
+	return m, tea.Tick(toastFor, func(time.Time) tea.Msg { return toastMsg(seq) })
+}
+
+// writeClipboard sends s to the terminal as an OSC 52 "set clipboard"
+// sequence. w is the PTY the program draws to; writes to it are serialized,
+// so this can't land in the middle of a frame.
+func writeClipboard(w io.Writer, s string) tea.Cmd {
+	return func() tea.Msg {
+		// OSC 52 ; c ; base64 ST
+		_, _ = io.WriteString(w, esc+"]52;c;"+base64.StdEncoding.EncodeToString([]byte(s))+bel)
+		return nil
+	}
+}
+
+// markSelected highlights s if it is the i'th copy item and the selection
+// is on it, with arrows as well as color so it shows without color too.
+func (m model) markSelected(i int, s string) string {
+	if i != m.copySel {
+		return s
+	}
+	return m.styles.cursor.Render(m.glyphs.right) + " " + s + " " + m.styles.cursor.Render(m.glyphs.left)
+}
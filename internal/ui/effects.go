@@ -0,0 +1,424 @@
+package ui
+
+import (
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// introEffect is one intro animation. Effects are pure functions of the
+// frame number, so any frame can be rendered on its own and replays the same
+// way for the same seed.
+type introEffect interface {
+	// frame renders frame n (starting at 0) and reports whether the
+	// animation has finished. Frames past the end repeat the last one.
+	frame(n int) (string, bool)
+}
+
+// effectContext is everything an effect may draw with.
+type effectContext struct {
+	text       string
+	styles     styles
+	glyphs     glyphs
+	renderer   *lipgloss.Renderer
+	seed       uint64
+	blinkTicks int // typewriter: cursor-only frames before typing
+}
+
+// scramble is a random stand-in for g, as many cells wide as g.
+func scramble(r *rand.Rand, g grapheme) string {
+	out := make([]byte, max(g.width, 1))
+	for i := range out {
+		out[i] = scrambleASCII[r.IntN(len(scrambleASCII))]
+	}
+	return string(out)
+}
+
+// introEffects are the effects the intro: block can name.
+var introEffects = map[string]func(effectContext) introEffect{
+	"typewriter": newTypewriter,
+	"matrix":     newMatrixRain,
+	"decrypt":    newDecrypt,
+	"figlet":     newFiglet,
+	"fade":       newFade,
+}
+
+// pickEffect builds the named effect. "random" picks one using the seed;
+// anything unknown falls back to the typewriter.
+func pickEffect(name string, ctx effectContext) introEffect {
+	if name == "random" {
+		names := make([]string, 0, len(introEffects))
+		for n := range introEffects {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		name = names[rand.New(rand.NewPCG(ctx.seed, 0)).IntN(len(names))]
+	}
+	if newEffect, ok := introEffects[name]; ok {
+		return newEffect(ctx)
+	}
+	return newTypewriter(ctx)
+}
+
+// frameRand is a deterministic source for frame n of an effect.
+func frameRand(seed uint64, n int) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, uint64(n)))
+}
+
+// ---- typewriter ------------------------------------------------------------
+
+// typewriter blinks a block cursor for a bit, then types the text out one
+// character every other frame.
+type typewriter struct {
+	effectContext
+	chars []grapheme
+}
+
+func newTypewriter(ctx effectContext) introEffect {
+	return typewriter{effectContext: ctx, chars: splitGraphemes(ctx.text)}
+}
+
+func (t typewriter) frame(n int) (string, bool) {
+	typed, done := 0, false
+	cursorOn := (n/3)%2 == 0
+
+	if k := n - t.blinkTicks; k > 0 {
+		typed = min(k/2, len(t.chars))
+		if typed == len(t.chars) {
+			done = true
+			cursorOn = true // keep cursor visible at end of name
+		}
+	}
+
+	line := t.styles.introName.Render(joinGraphemes(t.chars[:typed]))
+	if cursorOn {
+		line += t.styles.cursor.Render(t.glyphs.cursor)
+	}
+	return line, done
+}
+
+// ---- decrypt ---------------------------------------------------------------
+
+// decrypt shows scrambled characters that lock into place one by one, in a
+// random order.
+type decrypt struct {
+	effectContext
+	chars  []grapheme
+	lockAt []int // frame each character settles on
+	last   int
+}
+
+const scrambleASCII = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789#$%&*+=?"
+
+func newDecrypt(ctx effectContext) introEffect {
+	d := decrypt{effectContext: ctx, chars: splitGraphemes(ctx.text)}
+
+	order := rand.New(rand.NewPCG(ctx.seed, 1)).Perm(len(d.chars))
+	d.lockAt = make([]int, len(d.chars))
+	for i, pos := range order {
+		d.lockAt[pos] = 8 + i*2
+		d.last = max(d.last, d.lockAt[pos])
+	}
+	return d
+}
+
+func (d decrypt) frame(n int) (string, bool) {
+	r := frameRand(d.seed, n)
+
+	var b strings.Builder
+	for i, c := range d.chars {
+		switch {
+		case n >= d.lockAt[i] || c.blank():
+			b.WriteString(d.styles.introName.Render(c.text))
+		default:
+			b.WriteString(d.styles.cursor.Render(scramble(r, c)))
+		}
+	}
+	return b.String(), n >= d.last
+}
+
+// ---- matrix rain -----------------------------------------------------------
+
+// matrixRain drops columns of characters past the text, which locks in
+// letter by letter on the middle row. Once every letter is in, the rain
+// clears.
+type matrixRain struct {
+	effectContext
+	chars         []grapheme
+	cellOf        []int // text cell -> index into chars, -1 for the tail of a wide one
+	width, height int
+	row, col      int   // where the text sits
+	offset, speed []int // per column
+	lockAt        []int
+	last          int
+}
+
+const (
+	rainHeight = 9
+	rainTrail  = 4
+	rainStart  = 15 // frames of pure rain before letters lock in
+)
+
+func newMatrixRain(ctx effectContext) introEffect {
+	m := matrixRain{
+		effectContext: ctx,
+		chars:         splitGraphemes(strings.ReplaceAll(ctx.text, "\n", " ")),
+		height:        rainHeight,
+		row:           rainHeight / 2,
+	}
+	for i, c := range m.chars {
+		m.cellOf = append(m.cellOf, i)
+		for range c.width - 1 {
+			m.cellOf = append(m.cellOf, -1)
+		}
+	}
+	m.width = max(len(m.cellOf)+12, 40)
+	m.col = (m.width - len(m.cellOf)) / 2
+
+	r := rand.New(rand.NewPCG(ctx.seed, 2))
+	m.offset = make([]int, m.width)
+	m.speed = make([]int, m.width)
+	for c := range m.width {
+		m.offset[c] = r.IntN(m.height + rainTrail)
+		m.speed[c] = 1 + r.IntN(2)
+	}
+
+	m.lockAt = make([]int, len(m.chars))
+	for i, pos := range r.Perm(len(m.chars)) {
+		m.lockAt[pos] = rainStart + i*2
+		m.last = max(m.last, m.lockAt[pos])
+	}
+	return m
+}
+
+func (m matrixRain) frame(n int) (string, bool) {
+	done := n > m.last
+	r := frameRand(m.seed, n)
+	rain := func() string { return scramble(r, grapheme{width: 1}) }
+
+	rows := make([]string, m.height)
+	for y := range m.height {
+		var b strings.Builder
+		for x := 0; x < m.width; x++ {
+			if y == m.row && x >= m.col && x < m.col+len(m.cellOf) {
+				if i := m.cellOf[x-m.col]; i >= 0 && n >= m.lockAt[i] {
+					b.WriteString(m.styles.introName.Render(m.chars[i].text))
+					x += max(m.chars[i].width, 1) - 1 // the character covers its tail cells
+					continue
+				}
+			}
+			if done {
+				b.WriteByte(' ')
+				continue
+			}
+
+			head := (m.offset[x] + n*m.speed[x]) % (m.height + rainTrail)
+			switch dist := head - y; {
+			case dist == 0:
+				b.WriteString(m.styles.introName.Render(rain()))
+			case dist > 0 && dist < rainTrail:
+				b.WriteString(m.styles.dotInactive.Render(rain()))
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		rows[y] = b.String()
+	}
+	return strings.Join(rows, "\n"), done
+}
+
+// ---- figlet ----------------------------------------------------------------
+
+// figlet spells the text in big block letters, wiping them in from the
+// left. Text the built-in font can't draw falls back to decrypt.
+type figlet struct {
+	effectContext
+	rows  []string // the finished banner, '#' for ink
+	width int
+}
+
+const figletMaxWidth = appWidth - 4
+
+func newFiglet(ctx effectContext) introEffect {
+	rows, ok := bigText(strings.ReplaceAll(ctx.text, "\n", " "), figletMaxWidth)
+	if !ok {
+		return newDecrypt(ctx)
+	}
+	f := figlet{effectContext: ctx, rows: rows}
+	for _, row := range rows {
+		f.width = max(f.width, len(row))
+	}
+	return f
+}
+
+func (f figlet) frame(n int) (string, bool) {
+	shown := min(n*2, f.width)
+	ink := f.styles.introName.Render(f.glyphs.block)
+
+	lines := make([]string, len(f.rows))
+	for y, row := range f.rows {
+		var b strings.Builder
+		for x := range min(shown, len(row)) {
+			if row[x] == '#' {
+				b.WriteString(ink)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n"), shown == f.width
+}
+
+// bigText lays text out in the block font, wrapping between words so no
+// line is wider than maxWidth. It reports false if some character isn't in
+// the font.
+func bigText(text string, maxWidth int) ([]string, bool) {
+	var out []string
+	var line [figletHeight]string
+
+	flush := func() {
+		if line[0] == "" {
+			return
+		}
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		for _, row := range line {
+			out = append(out, strings.TrimRight(row, " "))
+		}
+		line = [figletHeight]string{}
+	}
+
+	for _, word := range strings.Fields(strings.ToUpper(text)) {
+		var w [figletHeight]string
+		for _, c := range word {
+			g, ok := figletFont[c]
+			if !ok {
+				return nil, false
+			}
+			for y := range figletHeight {
+				w[y] += g[y] + " "
+			}
+		}
+		if line[0] != "" && len(line[0])+len(w[0])+2 > maxWidth {
+			flush()
+		}
+		for y := range figletHeight {
+			if line[y] != "" {
+				line[y] += "  "
+			}
+			line[y] += w[y]
+		}
+	}
+	flush()
+	return out, len(out) > 0
+}
+
+const figletHeight = 5
+
+var figletFont = map[rune][figletHeight]string{
+	'A':  {" ### ", "#   #", "#####", "#   #", "#   #"},
+	'B':  {"#### ", "#   #", "#### ", "#   #", "#### "},
+	'C':  {" ####", "#    ", "#    ", "#    ", " ####"},
+	'D':  {"#### ", "#   #", "#   #", "#   #", "#### "},
+	'E':  {"#####", "#    ", "#### ", "#    ", "#####"},
+	'F':  {"#####", "#    ", "#### ", "#    ", "#    "},
+	'G':  {" ####", "#    ", "#  ##", "#   #", " ####"},
+	'H':  {"#   #", "#   #", "#####", "#   #", "#   #"},
+	'I':  {"#####", "  #  ", "  #  ", "  #  ", "#####"},
+	'J':  {"#####", "   # ", "   # ", "#  # ", " ##  "},
+	'K':  {"#   #", "#  # ", "###  ", "#  # ", "#   #"},
+	'L':  {"#    ", "#    ", "#    ", "#    ", "#####"},
+	'M':  {"#   #", "## ##", "# # #", "#   #", "#   #"},
+	'N':  {"#   #", "##  #", "# # #", "#  ##", "#   #"},
+	'O':  {" ### ", "#   #", "#   #", "#   #", " ### "},
+	'P':  {"#### ", "#   #", "#### ", "#    ", "#    "},
+	'Q':  {" ### ", "#   #", "# # #", "#  # ", " ## #"},
+	'R':  {"#### ", "#   #", "#### ", "#  # ", "#   #"},
+	'S':  {" ####", "#    ", " ### ", "    #", "#### "},
+	'T':  {"#####", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U':  {"#   #", "#   #", "#   #", "#   #", " ### "},
+	'V':  {"#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W':  {"#   #", "#   #", "# # #", "## ##", "#   #"},
+	'X':  {"#   #", " # # ", "  #  ", " # # ", "#   #"},
+	'Y':  {"#   #", " # # ", "  #  ", "  #  ", "  #  "},
+	'Z':  {"#####", "   # ", "  #  ", " #   ", "#####"},
+	'0':  {" ### ", "#  ##", "# # #", "##  #", " ### "},
+	'1':  {"  #  ", " ##  ", "  #  ", "  #  ", " ### "},
+	'2':  {" ### ", "#   #", "  ## ", " #   ", "#####"},
+	'3':  {"#### ", "    #", " ### ", "    #", "#### "},
+	'4':  {"#   #", "#   #", "#####", "    #", "    #"},
+	'5':  {"#####", "#    ", "#### ", "    #", "#### "},
+	'6':  {" ### ", "#    ", "#### ", "#   #", " ### "},
+	'7':  {"#####", "    #", "   # ", "  #  ", "  #  "},
+	'8':  {" ### ", "#   #", " ### ", "#   #", " ### "},
+	'9':  {" ### ", "#   #", " ####", "    #", " ### "},
+	'.':  {"  ", "  ", "  ", "  ", "# "},
+	'-':  {"    ", "    ", "####", "    ", "    "},
+	'\'': {"#", "#", " ", " ", " "},
+}
+
+// ---- fade ------------------------------------------------------------------
+
+// fade brings the text in from the background color, left to right, ending
+// on a gradient between the name and accent colors.
+type fade struct {
+	effectContext
+	chars []grapheme
+	from  colorful.Color   // background
+	to    []colorful.Color // final color per character
+}
+
+const (
+	fadeFrames  = 12 // frames for one character to fade in
+	fadeStagger = 1  // frames between neighbouring characters starting
+)
+
+func newFade(ctx effectContext) introEffect {
+	f := fade{effectContext: ctx, chars: splitGraphemes(ctx.text)}
+
+	pick := func(c lipgloss.AdaptiveColor) colorful.Color {
+		hex := c.Light
+		if ctx.renderer.HasDarkBackground() {
+			hex = c.Dark
+		}
+		col, _ := colorful.Hex(hex)
+		return col
+	}
+	f.from, _ = colorful.Hex("#FFFFFF")
+	if ctx.renderer.HasDarkBackground() {
+		f.from, _ = colorful.Hex("#000000")
+	}
+
+	start, end := pick(colorName), pick(colorAccent)
+	f.to = make([]colorful.Color, len(f.chars))
+	for i := range f.chars {
+		t := 0.0
+		if len(f.chars) > 1 {
+			t = float64(i) / float64(len(f.chars)-1)
+		}
+		f.to[i] = start.BlendLab(end, t).Clamped()
+	}
+	return f
+}
+
+func (f fade) frame(n int) (string, bool) {
+	var b strings.Builder
+	for i, c := range f.chars {
+		if c.blank() {
+			b.WriteString(c.text)
+			continue
+		}
+		t := min(max(float64(n-i*fadeStagger)/fadeFrames, 0), 1)
+		col := f.from.BlendLab(f.to[i], t).Clamped()
+		b.WriteString(f.renderer.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(col.Hex())).
+			Render(c.text))
+	}
+	return b.String(), n >= (len(f.chars)-1)*fadeStagger+fadeFrames
+}
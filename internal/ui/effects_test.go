@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
+)
+
+// maxEffectFrames is far more than any effect takes with the default
+// settings; an effect still running by then never finishes.
+const maxEffectFrames = 2000
+
+func testEffect(name, text string, profile termenv.Profile) introEffect {
+	r := testRenderer(profile, true)
+	return pickEffect(name, effectContext{
+		text:       text,
+		styles:     newStyles(r, unicodeGlyphs),
+		glyphs:     unicodeGlyphs,
+		renderer:   r,
+		seed:       1,
+		blinkTicks: 4,
+	})
+}
+
+// lastFrame runs e to the end and returns the frame it finished on.
+func lastFrame(t *testing.T, e introEffect) int {
+	t.Helper()
+	for n := 0; n < maxEffectFrames; n++ {
+		if _, done := e.frame(n); done {
+			return n
+		}
+	}
+	t.Fatalf("not done after %d frames", maxEffectFrames)
+	return 0
+}
+
+// TestEffectFrames records a handful of frames of every effect, the last
+// one included, in one golden file per effect.
+func TestEffectFrames(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		profile termenv.Profile
+	}{
+		{"typewriter", termenv.Ascii},
+		{"decrypt", termenv.Ascii},
+		{"matrix", termenv.Ascii},
+		{"figlet", termenv.Ascii},
+		{"fade", termenv.ANSI256}, // all color, nothing to see without it
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := testEffect(tc.name, "Janet Doe\nBackend Engineer", tc.profile)
+			end := lastFrame(t, e)
+
+			var b strings.Builder
+			for _, n := range []int{0, 1, 2, 5, 10, end / 2, end, end + 1} {
+				frame, done := e.frame(n)
+				fmt.Fprintf(&b, "--- frame %d (done: %t)\n%s\n", n, done, frame)
+			}
+			golden.RequireEqual(t, []byte(b.String()))
+		})
+	}
+}
+
+// TestEffectsPure checks that a frame depends on nothing but its number
+// and the seed, so a redraw of the same frame doesn't flicker.
+func TestEffectsPure(t *testing.T) {
+	for name := range introEffects {
+		t.Run(name, func(t *testing.T) {
+			a := testEffect(name, "Janet Doe", termenv.TrueColor)
+			b := testEffect(name, "Janet Doe", termenv.TrueColor)
+			end := lastFrame(t, a)
+			for n := end; n >= 0; n-- { // backwards, so no frame leans on the last
+				fa, _ := a.frame(n)
+				fb, _ := b.frame(n)
+				if fa != fb {
+					t.Fatalf("frame %d differs between two effects with the same seed", n)
+				}
+				if again, _ := a.frame(n); again != fa {
+					t.Fatalf("frame %d differs when drawn twice", n)
+				}
+			}
+		})
+	}
+}
+
+func TestPickEffect(t *testing.T) {
+	if got := reflect.TypeOf(testEffect("nonsense", "x", termenv.Ascii)); got != reflect.TypeOf(typewriter{}) {
+		t.Errorf("unknown effect gave %v, want the typewriter", got)
+	}
+	first := reflect.TypeOf(testEffect("random", "x", termenv.Ascii))
+	for range 5 {
+		if got := reflect.TypeOf(testEffect("random", "x", termenv.Ascii)); got != first {
+			t.Fatalf("random picked %v and then %v for the same seed", first, got)
+		}
+	}
+}
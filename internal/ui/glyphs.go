@@ -0,0 +1,54 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// glyphs are the decorative characters the UI draws with. Terminals that
+// can't show UTF-8 get the ASCII set instead of mojibake.
+type glyphs struct {
+	cursor      string // intro typing cursor
+	block       string // ink for the figlet intro
+	bullet      string // list items, footer separators
+	activeDot   string // paginator
+	inactiveDot string
+	dash        string // "Company — Role"
+	enDash      string // ranges like "1–4"
+	sep         string // "2023 · Remote"
+	left, right string // footer key hints
+	up, down    string
+	rec         string // the session is being recorded
+	border      lipgloss.Border
+}
+
+var unicodeGlyphs = glyphs{
+	cursor:      "█",
+	block:       "█",
+	bullet:      "•",
+	activeDot:   "●",
+	inactiveDot: "•",
+	dash:        "—",
+	enDash:      "–",
+	sep:         "·",
+	left:        "←",
+	right:       "→",
+	up:          "↑",
+	down:        "↓",
+	rec:         "●",
+	border:      lipgloss.NormalBorder(),
+}
+
+var asciiGlyphs = glyphs{
+	cursor:      "#",
+	block:       "#",
+	bullet:      "*",
+	activeDot:   "o",
+	inactiveDot: ".",
+	dash:        "-",
+	enDash:      "-",
+	sep:         "|",
+	left:        "<",
+	right:       ">",
+	up:          "^",
+	down:        "v",
+	rec:         "*",
+	border:      lipgloss.ASCIIBorder(),
+}
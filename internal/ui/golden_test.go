@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+// Golden files live in testdata/<test name>.golden; run
+// `go test ./internal/ui -update` to rewrite them after a deliberate change
+// to the layout, and read the diff before committing it.
+
+func TestGoldenTabs(t *testing.T) {
+	p := loadFixture(t, "full")
+	book, err := guestbook.Open(filepath.Join(t.TempDir(), "guestbook.log"), guestbook.Options{AutoApprove: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer book.Close()
+	posted := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+	if _, err := book.Post("SHA256:abc", "ada", "Lovely card, hi from Lisbon!", posted); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		keys []tea.Msg
+	}{
+		{"overview", nil},
+		{"experience", press("2")},
+		{"experience_page2", press("2", "j")},
+		{"projects", press("3")},
+		{"projects_page2", press("3", "j")},
+		{"contact", press("4")},
+		{"contact_selected", press("4", "j", "j")},
+		{"guestbook", press("5")},
+		{"shell", press("6")},
+		{"shell_ls", append(press("6", "enter"), append(typed("ls"), press("enter")...)...)},
+		{"accessible", press("a")},
+		{"qr", press("4", "o")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestModel(t, p, Options{SkipIntro: true, Guestbook: book})
+			m = drive(t, m, 120, 40, tc.keys...)
+			golden.RequireEqual(t, []byte(m.View()))
+		})
+	}
+}
+
+func TestGoldenSizes(t *testing.T) {
+	p := loadFixture(t, "full")
+	for _, tc := range []struct {
+		name          string
+		width, height int
+	}{
+		{"unsized", 0, 0},
+		{"80x24", 80, 24},
+		{"120x40", 120, 40},
+		{"200x50", 200, 50},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestModel(t, p, Options{SkipIntro: true})
+			m = drive(t, m, tc.width, tc.height, press("2")...)
+			golden.RequireEqual(t, []byte(m.View()))
+		})
+	}
+}
+
+func TestGoldenResize(t *testing.T) {
+	p := loadFixture(t, "full")
+	m := newTestModel(t, p, Options{SkipIntro: true})
+	m = drive(t, m, 200, 50, tea.WindowSizeMsg{Width: 80, Height: 24})
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestGoldenASCII(t *testing.T) {
+	p := loadFixture(t, "full")
+	m := newTestModel(t, p, Options{SkipIntro: true, ASCII: true})
+	m = drive(t, m, 120, 40, press("2")...)
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestGoldenRecording(t *testing.T) {
+	p := loadFixture(t, "full")
+	m := newTestModel(t, p, Options{SkipIntro: true, Recording: true})
+	m = drive(t, m, 120, 40, press("4")...)
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestGoldenContactForm(t *testing.T) {
+	p := loadFixture(t, "full")
+	q, err := contact.NewQueue(t.TempDir(), nil, contact.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(t, p, Options{SkipIntro: true, Contact: q})
+	m = drive(t, m, 120, 40, append(press("4", "m"), typed("Ada")...)...)
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+// TestGoldenEmpty is a portfolio with nothing but a name: every tab has to
+// say so rather than draw an empty box or panic.
+func TestGoldenEmpty(t *testing.T) {
+	p := loadFixture(t, "empty")
+	for _, tab := range []string{"1", "2", "3", "4", "5"} {
+		t.Run("tab"+tab, func(t *testing.T) {
+			m := newTestModel(t, p, Options{SkipIntro: true})
+			m = drive(t, m, 120, 40, press(tab)...)
+			golden.RequireEqual(t, []byte(m.View()))
+		})
+	}
+}
+
+// TestGoldenIntro steps the intro by hand rather than through a program:
+// ticks come from a timer there, and a frame is only worth comparing if it
+// is the same one every run.
+func TestGoldenIntro(t *testing.T) {
+	p := loadFixture(t, "full")
+	m := newTestModel(t, p, Options{})
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = next.(model)
+
+	tick := 0
+	for _, at := range []int{0, 3, 30, 200} {
+		for ; tick < at; tick++ {
+			next, _ = m.Update(tickMsg{})
+			m = next.(model)
+		}
+		t.Run("tick"+strconv.Itoa(at), func(t *testing.T) {
+			golden.RequireEqual(t, []byte(m.View()))
+		})
+	}
+	if m.loading {
+		t.Error("intro still running after 200 ticks")
+	}
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	guestbookTab   = 4
+	guestbookLines = appHeight - 5 - 4 // content height, less the input and status lines
+)
+
+func newGuestbookInput(r *lipgloss.Renderer, st styles) textinput.Model {
+	in := textinput.New()
+	in.Placeholder = "Say hi"
+	in.CharLimit = guestbook.MaxLen
+	in.Width = appWidth - 10
+	in.Prompt = "> "
+
+	// textinput defaults to lipgloss' global renderer, which describes the
+	// server, not the visitor
+	in.PromptStyle = st.cursor
+	in.TextStyle = r.NewStyle()
+	in.PlaceholderStyle = st.meta
+	in.Cursor.Style = st.cursor
+	in.Cursor.TextStyle = r.NewStyle()
+	in.Cursor.SetMode(cursor.CursorStatic) // no blink ticks to send over the wire
+	return in
+}
+
+// updateGuestbookInput handles a key while the visitor is typing a message.
+func (m model) updateGuestbookInput(msg tea.KeyMsg, now time.Time) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.gbInput.Blur()
+		m.gbStatus = ""
+		return m, nil
+	case "enter":
+		e, err := m.guestbook.Post(m.fingerprint, m.username, m.gbInput.Value(), now)
+		if err != nil {
+			m.gbStatus = "Not posted: " + err.Error()
+			return m, nil
+		}
+		m.gbInput.Reset()
+		m.gbInput.Blur()
+		m.gbStatus = "Thanks! Your message will show up once it's approved."
+		if e.Approved {
+			m.gbStatus = "Thanks for signing!"
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.gbInput, cmd = m.gbInput.Update(msg)
+	return m, cmd
+}
+
+// startGuestbookInput focuses the message box, if this visitor can post.
+func (m model) startGuestbookInput() (model, tea.Cmd) {
+	if m.fingerprint == "" {
+		m.gbStatus = "Connect with an SSH key to sign the guestbook."
+		return m, nil
+	}
+	m.gbStatus = ""
+	return m, m.gbInput.Focus()
+}
+
+func (m model) viewGuestbook() string {
+	lines := []string{m.styles.header.Render("Guestbook"), ""}
+
+	entries := m.guestbook.Latest(guestbookLines)
+	if len(entries) == 0 {
+		lines = append(lines, m.styles.meta.Render("No messages yet. Be the first!"))
+	}
+	used := 0
+	for _, e := range entries {
+		msg := m.styles.wrap.Render(e.Message)
+		n := 2 + lipgloss.Height(msg) // meta line, message, blank
+		if used+n > guestbookLines {
+			break
+		}
+		used += n
+		meta := fmt.Sprintf("%s %s %s", e.User, m.glyphs.sep, e.Time.Format("Jan 2, 2006"))
+		lines = append(lines, m.styles.meta.Render(meta), msg, "")
+	}
+
+	for len(lines) < guestbookLines+2 {
+		lines = append(lines, "")
+	}
+	switch {
+	case m.gbInput.Focused():
+		lines = append(lines, m.gbInput.View())
+		if m.gbStatus != "" {
+			lines = append(lines, m.gbStatus)
+		} else {
+			lines = append(lines, m.styles.meta.Render("enter: post  "+m.glyphs.bullet+"  esc: cancel"))
+		}
+	case m.gbStatus != "":
+		lines = append(lines, m.gbStatus)
+	default:
+		lines = append(lines, m.styles.meta.Render("w: sign the guestbook"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m model) a11yGuestbook(add func(...string)) {
+	add("Guestbook", "")
+	entries := m.guestbook.Latest(guestbookLines)
+	if len(entries) == 0 {
+		add("No messages yet.")
+	}
+	for _, e := range entries {
+		add(fmt.Sprintf("%s wrote on %s: %s", e.User, e.Time.Format("January 2, 2006"), e.Message))
+	}
+	add("")
+	switch {
+	case m.gbInput.Focused():
+		add("Your message: " + m.gbInput.Value())
+		if m.gbStatus != "" {
+			add(m.gbStatus)
+		}
+		add("Press enter to post or escape to cancel.")
+	case m.gbStatus != "":
+		add(m.gbStatus)
+	default:
+		add("Press w to sign the guestbook.")
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"time"
+	"unicode"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Defaults for the intro, used for anything the intro: block leaves out.
+const (
+	defaultIntroTick  = 60 * time.Millisecond
+	defaultIntroBlink = 1200 * time.Millisecond // cursor-only blinking
+	defaultIntroPause = 600 * time.Millisecond  // full name shown before the card
+)
+
+// introSettings resolves the intro: block against the defaults.
+type introSettings struct {
+	enabled    bool
+	effect     string
+	text       string
+	tick       time.Duration
+	blinkTicks int
+	pauseTicks int
+	color      string
+	cursor     string
+}
+
+func newIntroSettings(p *portfolio.Portfolio) introSettings {
+	in := p.Intro
+	s := introSettings{
+		enabled: in.Enabled == nil || *in.Enabled,
+		effect:  in.Effect,
+		text:    p.Name,
+		tick:    in.Tick,
+		color:   in.Color,
+		cursor:  in.Cursor,
+	}
+	if in.Tagline && p.Tagline != "" {
+		s.text += "\n" + p.Tagline
+	}
+	if s.text == "" {
+		s.enabled = false
+	}
+
+	if s.tick <= 0 {
+		s.tick = defaultIntroTick
+	}
+	blink, pause := in.Blink, in.Pause
+	if blink <= 0 {
+		blink = defaultIntroBlink
+	}
+	if pause <= 0 {
+		pause = defaultIntroPause
+	}
+	s.blinkTicks = max(int(blink/s.tick), 1)
+	s.pauseTicks = max(int(pause/s.tick), 1)
+
+	return s
+}
+
+// apply lets the intro: block restyle the name and cursor in the intro.
+// The card keeps its own name style, so t still recolors it, and an ASCII
+// terminal keeps its cursor unless the configured one is ASCII too.
+func (s introSettings) apply(st styles, g glyphs, ascii bool) (styles, glyphs) {
+	if s.color != "" {
+		st.introName = st.introName.Foreground(lipgloss.Color(s.color))
+	}
+	if s.cursor != "" && (!ascii || isASCII(s.cursor)) {
+		g.cursor = s.cursor
+	}
+	return st, g
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// skipIntro jumps straight to the card.
+func (m model) skipIntro() model {
+	m.loading = false
+	return m
+}
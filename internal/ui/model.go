@@ -1,78 +1,241 @@
 package ui
 
 import (
+	"io"
 	"time"
 
+	"github.com/Shbhom/ssh-portfolio/internal/analytics"
+	"github.com/Shbhom/ssh-portfolio/internal/contact"
+	"github.com/Shbhom/ssh-portfolio/internal/guestbook"
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
 	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
-	"github.com/charmbracelet/bubbles/help"
+	"github.com/Shbhom/ssh-portfolio/internal/shell"
 	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 const (
-	pauseTicks = 10 // ~1 second at 70ms per tick
-	appWidth   = 100
-	appHeight  = 20
+	appWidth  = 100
+	appHeight = 20
 )
 
 type tickMsg time.Time
 
 type model struct {
 	username string
-	keys     keyMap
-	help     help.Model
 	quitting bool
 	loading  bool // will be true until progress bar completes
 	width    int
 	height   int
 
 	// intro animation state
-	introText  string // "Shubhom Srivastava"
-	typedChars int    // how many characters of introText are visible
-	cursorOn   bool   // whether to draw the cursor
-	phase      int    // 0 = blink only, 1 = typing, 2 = done
-	frameCount int    // counts ticks to control timing
-
-	// styles
-	nameStyle   lipgloss.Style
-	cursorStyle lipgloss.Style
-
-	activeTab int // 0 = Overview, 1 = Experience, 2 = Projects, 3 = Contact
-	portfolio *portfolio.Portfolio
+	intro       introEffect
+	introFrame  int           // frames drawn so far
+	introDoneAt int           // frame the effect finished on, -1 while running
+	tick        time.Duration // time per frame
+	pauseTicks  int           // frames the finished effect stays up
+
+	// styles, built from the session's renderer
+	renderer *lipgloss.Renderer
+	styles   styles
+	glyphs   glyphs
+	ascii    bool // glyphs are the ASCII set
+
+	accessible   bool            // linear, colorless layout for screen readers
+	colorProfile termenv.Profile // what the terminal supports, restored when leaving accessible mode
+
+	activeTab int                 // one of the *Tab constants, which index tabLabels
+	portfolio *portfolio.Snapshot // shared with other sessions, read-only
 	expList   paginator.Model
 	projList  paginator.Model
+
+	// session timeouts, zero means disabled
+	idleTimeout    time.Duration
+	maxTimeout     time.Duration
+	started        time.Time
+	lastInput      time.Time
+	timeoutWarning string // countdown shown in place of the footer
+	idleWarning    bool   // warning is the idle one, so a key cancels it
+	quitReason     string
+
+	events   *analytics.Recorder
+	session  SessionInfo
+	tabSince time.Time // when the current tab was opened
+
+	guestbook   *guestbook.Book // nil when the server keeps none
+	fingerprint string          // visitor's SSH key, empty if they had none
+	gbInput     textinput.Model
+	gbStatus    string // outcome of the last post
+
+	contact  *contact.Queue // nil when the server can't deliver messages
+	form     contactForm
+	remoteIP string // counts messages from visitors without a key
+
+	clipboard io.Writer // the visitor's terminal, for OSC 52; nil when it can't take it
+	copySel   int       // selected copyItem on the current tab
+	toast     string    // shown in place of the footer for a few seconds
+	toastSeq  int
+	qr        *qrView // full-screen QR code, nil when closed
+
+	sh        *shell.Shell // the Shell tab's filesystem, directory and history
+	shInput   textinput.Model
+	shLines   []string // scrollback
+	shHistory int      // position in sh.History while walking it
+
+	recording bool // show the recording indicator
+
+	cards *ViewCache // rendered cards, usually shared with other sessions
+}
+
+// SessionInfo describes the visitor's client for the session_start event.
+type SessionInfo struct {
+	ClientVersion string
+	Term          string
+	Width         int
+	Height        int
+}
+
+// Options tunes a model for the session it is serving.
+type Options struct {
+	IdleTimeout time.Duration // quit after this long without a keypress
+	MaxTimeout  time.Duration // quit this long after the session started
+
+	Events  *analytics.Recorder // nil disables analytics
+	Session SessionInfo
+
+	Renderer *lipgloss.Renderer // the visitor's terminal, nil for the process default
+	ASCII    bool               // the terminal can't show UTF-8
+
+	Accessible bool // start in the screen-reader-friendly layout
+	SkipIntro  bool // returning visitor, go straight to the card
+
+	Seed uint64 // drives the randomized intro effects; zero picks one per session
+
+	Guestbook      *guestbook.Book // adds the Guestbook tab
+	KeyFingerprint string          // the visitor's SSH key; without one they can read but not post
+
+	Contact  *contact.Queue // enables the form on the Contact tab
+	RemoteIP string         // the visitor's address, which the form's limit falls back to without a key
+
+	Clipboard io.Writer // where `c` sends OSC 52; nil shows the value instead
+
+	Recording bool // the server is recording the session; the footer says so
+
+	Cards *ViewCache // shared by the server's sessions; nil gives this one a cache of its own
 }
 
-func NewModel(userName string, p *portfolio.Portfolio) model {
-
-	expPager := newPaginator(len(p.Experiences))
-	projPager := newPaginator(len(p.Projects))
-
-	return model{
-		username:   userName,
-		keys:       keys,
-		help:       help.New(),
-		loading:    true,
-		introText:  "Shubhom Srivastava", // what we’ll type out
-		typedChars: 0,
-		cursorOn:   true,
-		phase:      0, // start in blink-only phase
-		frameCount: 0,
-		activeTab:  0,
-
-		nameStyle: nameStyle,
-
-		cursorStyle: cursorStyle,
-		portfolio:   p,
-		expList:     expPager,
-		projList:    projPager,
+func NewModel(userName string, p *portfolio.Snapshot, opts Options) model {
+
+	r := opts.Renderer
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+	g := unicodeGlyphs
+	if opts.ASCII {
+		g = asciiGlyphs
+	}
+	intro := newIntroSettings(p.Portfolio)
+	st, g := intro.apply(newStyles(r, g), g, opts.ASCII)
+
+	expPager := newPaginator(len(p.Experiences), st, g)
+	projPager := newPaginator(len(p.Projects), st, g)
+	sh := shell.New(p.Portfolio, userName, g.sep)
+	now := time.Now()
+	seed := opts.Seed
+	if seed == 0 {
+		seed = uint64(now.UnixNano())
 	}
+
+	m := model{
+		username: userName,
+		loading:  true,
+		intro: pickEffect(intro.effect, effectContext{
+			text:       intro.text,
+			styles:     st,
+			glyphs:     g,
+			renderer:   r,
+			seed:       seed,
+			blinkTicks: intro.blinkTicks,
+		}),
+		introDoneAt: -1,
+		tick:        intro.tick,
+		pauseTicks:  intro.pauseTicks,
+		activeTab:   overviewTab,
+
+		renderer:     r,
+		colorProfile: r.ColorProfile(),
+		styles:       st,
+		glyphs:       g,
+		ascii:        opts.ASCII,
+		portfolio:    p,
+		expList:      expPager,
+		projList:     projPager,
+
+		idleTimeout: opts.IdleTimeout,
+		maxTimeout:  opts.MaxTimeout,
+		started:     now,
+		lastInput:   now,
+
+		events:   opts.Events,
+		session:  opts.Session,
+		tabSince: now,
+
+		guestbook:   opts.Guestbook,
+		fingerprint: opts.KeyFingerprint,
+		gbInput:     newGuestbookInput(r, st),
+
+		contact:  opts.Contact,
+		form:     newContactForm(r, st),
+		remoteIP: opts.RemoteIP,
+
+		clipboard: opts.Clipboard,
+
+		sh:      sh,
+		shInput: newShellInput(r, st, sh),
+		shLines: []string{"Type help to see what you can do here."},
+
+		recording: opts.Recording,
+
+		cards: opts.Cards,
+	}
+	if m.cards == nil {
+		m.cards = NewViewCache()
+	}
+	if !intro.enabled || opts.SkipIntro {
+		m = m.skipIntro()
+	}
+	if opts.Accessible {
+		m = m.setAccessible(true)
+	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		tickCmd(), // your progress timer
-		tea.SetWindowTitle("Shubhom's Portfolio"), // 👈 window title
-	)
+	m.events.Emit(analytics.Event{
+		Kind:          analytics.KindSessionStart,
+		User:          m.username,
+		ClientVersion: m.session.ClientVersion,
+		Term:          m.session.Term,
+		Width:         m.session.Width,
+		Height:        m.session.Height,
+	})
+	metrics.TabViews.WithLabelValues(tabLabels[m.activeTab]).Inc()
+
+	title := "Portfolio"
+	if m.portfolio.Name != "" {
+		title = m.portfolio.Name + "'s Portfolio"
+	}
+	cmds := []tea.Cmd{
+		tea.SetWindowTitle(title), // 👈 window title
+	}
+	if m.loading {
+		cmds = append(cmds, tickCmd(m.tick))
+	}
+	if m.idleTimeout > 0 || m.maxTimeout > 0 {
+		cmds = append(cmds, clockCmd())
+	}
+	return tea.Batch(cmds...)
 }
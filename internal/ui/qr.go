@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"rsc.io/qr"
+)
+
+const (
+	qrQuiet    = 2 // modules of blank border; the spec asks for 4, phones cope with 2
+	qrMaxScale = 4
+)
+
+// qrView is the full-screen QR code for one copyItem, so a visitor on a
+// laptop can open it on their phone.
+type qrView struct {
+	item copyItem
+	code *qr.Code
+}
+
+// target is what a phone should open for the item.
+func (c copyItem) target() string {
+	switch {
+	case c.qr != "":
+		return c.qr
+	case c.link != "":
+		return c.link
+	}
+	return c.value
+}
+
+// telURI turns a phone number as people write it into a tel: URI.
+func telURI(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r == '+' || '0' <= r && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if digits == "" {
+		return ""
+	}
+	return "tel:" + digits
+}
+
+// openQR shows the QR code for the selected item.
+func (m model) openQR() (model, tea.Cmd) {
+	items := m.copyItems()
+	if len(items) == 0 {
+		return m, nil
+	}
+	item := items[min(m.copySel, len(items)-1)]
+
+	code, err := qr.Encode(item.target(), qr.M)
+	if err != nil {
+		return m.showToast("Can't make a QR code for " + item.label + ": " + err.Error())
+	}
+	m.qr = &qrView{item: item, code: code}
+	return m, nil
+}
+
+// updateQR handles a key while the QR code is up; only closing it does
+// anything.
+func (m model) updateQR(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "o":
+		m.qr = nil
+	}
+	return m, nil
+}
+
+// viewQR draws the code as large as the window allows, with what it holds
+// underneath.
+func (m model) viewQR() string {
+	caption := []string{
+		"",
+		m.qr.item.label + ": " + m.qr.item.value,
+		m.styles.meta.Render("esc: back"),
+	}
+
+	width, height := m.width, m.height
+	if width == 0 || height == 0 {
+		width, height = 80, 24 // no size yet, assume the classic
+	}
+	art, ok := m.qrArt(width, height-len(caption))
+	if !ok {
+		art = "This window is too small for the QR code."
+	}
+
+	return m.place(lipgloss.JoinVertical(lipgloss.Center, append([]string{art}, caption...)...))
+}
+
+// qrArt renders the code at the largest scale that fits maxW x maxH cells.
+// UTF-8 terminals get two modules per cell with half blocks, which keeps
+// modules square; ASCII ones get "##" per module.
+func (m model) qrArt(maxW, maxH int) (string, bool) {
+	code := m.qr.code
+	side := code.Size + 2*qrQuiet
+
+	// cells per module: across, and down (in halves for half blocks)
+	cellW, cellH := 1, 1
+	if m.ascii {
+		cellW, cellH = 2, 2
+	}
+	scale := 0
+	for s := 1; s <= qrMaxScale; s++ {
+		if side*s*cellW <= maxW && (side*s*cellH+1)/2 <= maxH {
+			scale = s
+		}
+	}
+	if scale == 0 {
+		return "", false
+	}
+
+	// Dark modules on a light background is what scanners expect. With
+	// color we paint exactly that; without, we draw in the terminal's own
+	// foreground, which is the light one on a dark background.
+	paint := m.renderer.NewStyle()
+	invert := false
+	if m.renderer.ColorProfile() == termenv.Ascii {
+		invert = m.renderer.HasDarkBackground()
+	} else {
+		paint = paint.Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("#FFFFFF"))
+	}
+	ink := func(x, y int) bool {
+		return code.Black(x/scale-qrQuiet, y/scale-qrQuiet) != invert
+	}
+
+	pixels := side * scale
+	var rows []string
+	var b strings.Builder
+	if m.ascii {
+		for y := 0; y < pixels; y++ {
+			b.Reset()
+			for x := 0; x < pixels; x++ {
+				if ink(x, y) {
+					b.WriteString("##")
+				} else {
+					b.WriteString("  ")
+				}
+			}
+			rows = append(rows, paint.Render(b.String()))
+		}
+		return strings.Join(rows, "\n"), true
+	}
+
+	for y := 0; y < pixels; y += 2 {
+		b.Reset()
+		for x := 0; x < pixels; x++ {
+			top, bottom := ink(x, y), y+1 < pixels && ink(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		rows = append(rows, paint.Render(b.String()))
+	}
+	return strings.Join(rows, "\n"), true
+}
+
+// qrHint is the key hint for the copy and QR keys, shown with the items.
+func (m model) qrHint(pick string) string {
+	sep := "  " + m.glyphs.sep + "  "
+	hint := "c: copy" + sep + "o: QR code"
+	if len(m.copyItems()) > 1 {
+		hint = pick + sep + hint
+	}
+	return m.styles.meta.Render(hint)
+}
+
+// a11yQRHint is qrHint for the accessible layout.
+func a11yQRHint(pick string, items int) string {
+	if items > 1 {
+		return fmt.Sprintf("Press %s to pick one, c to copy it, o to show it as a QR code.", pick)
+	}
+	return "Press c to copy it, o to show it as a QR code."
+}
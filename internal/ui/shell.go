@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Shbhom/ssh-portfolio/internal/shell"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	shellTab = 5
+
+	shellLines      = appHeight - 5 - 1 // content height, less the prompt
+	shellScrollback = 500               // lines kept for scrolling back over
+)
+
+func newShellInput(r *lipgloss.Renderer, st styles, sh *shell.Shell) textinput.Model {
+	in := textinput.New()
+	in.CharLimit = 200
+
+	// see newGuestbookInput
+	in.PromptStyle = st.cursor
+	in.TextStyle = r.NewStyle()
+	in.PlaceholderStyle = st.meta
+	in.Cursor.Style = st.cursor
+	in.Cursor.TextStyle = r.NewStyle()
+	in.Cursor.SetMode(cursor.CursorStatic)
+	return setShellPrompt(in, sh)
+}
+
+// setShellPrompt shows the prompt for the shell's current directory.
+func setShellPrompt(in textinput.Model, sh *shell.Shell) textinput.Model {
+	in.Prompt = sh.Prompt()
+	in.Width = appWidth - 6 - lipgloss.Width(in.Prompt)
+	return in
+}
+
+// updateShellInput handles a key while the prompt has focus.
+func (m model) updateShellInput(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.shInput.Blur()
+		return m, nil
+	case "enter":
+		return m.runShellLine()
+	case "tab":
+		line, options := m.sh.Complete(m.shInput.Value())
+		if len(options) > 1 {
+			m = m.shellPrint(m.shInput.Prompt+m.shInput.Value(), strings.Join(options, "  "))
+		}
+		m.shInput.SetValue(line)
+		m.shInput.CursorEnd()
+		return m, nil
+	case "up", "down":
+		return m.walkShellHistory(msg.String() == "up"), nil
+	case "ctrl+l":
+		m.shLines = nil
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.shInput, cmd = m.shInput.Update(msg)
+	return m, cmd
+}
+
+func (m model) runShellLine() (model, tea.Cmd) {
+	line := m.shInput.Value()
+	m = m.shellPrint(m.shInput.Prompt + line)
+	res := m.sh.Run(line)
+	if args := strings.Fields(line); len(args) > 1 && args[0] == "grep" {
+		m.trackSearch(args[1])
+	}
+
+	m.shInput.Reset()
+	m.shInput = setShellPrompt(m.shInput, m.sh)
+	m.shHistory = len(m.sh.History)
+
+	if res.Clear {
+		m.shLines = nil
+	}
+	if res.Output != "" {
+		m = m.shellPrint(strings.Split(res.Output, "\n")...)
+	}
+	if res.Copy != "" {
+		// like c on the other tabs, but reported here rather than in a toast
+		if m.clipboard == nil {
+			return m.shellPrint("Your terminal can't take clipboard data, so here it is: " + res.Copy), nil
+		}
+		m = m.shellPrint("Copied "+res.Copy+" to your clipboard.",
+			"(Nothing there? Your terminal may not allow OSC 52.)")
+		return m, writeClipboard(m.clipboard, res.Copy)
+	}
+	return m, nil
+}
+
+// walkShellHistory puts the previous (or next) line from the history at
+// the prompt; past the newest one the prompt is empty again.
+func (m model) walkShellHistory(back bool) model {
+	hist := m.sh.History
+	switch {
+	case back && m.shHistory > 0:
+		m.shHistory--
+	case !back && m.shHistory < len(hist):
+		m.shHistory++
+	default:
+		return m
+	}
+	if m.shHistory == len(hist) {
+		m.shInput.SetValue("")
+	} else {
+		m.shInput.SetValue(hist[m.shHistory])
+	}
+	m.shInput.CursorEnd()
+	return m
+}
+
+// shellPrint adds lines to the scrollback.
+func (m model) shellPrint(lines ...string) model {
+	// copy rather than append: the backing array is shared with earlier
+	// models
+	next := make([]string, 0, len(m.shLines)+len(lines))
+	next = append(next, m.shLines...)
+	next = append(next, lines...)
+	if over := len(next) - shellScrollback; over > 0 {
+		next = next[over:]
+	}
+	m.shLines = next
+	return m
+}
+
+// focusShell puts the cursor at the prompt. It only happens on request:
+// tabs wrap around, so a visitor can land here on the way elsewhere, and
+// the prompt would swallow their next q.
+func (m model) focusShell() (model, tea.Cmd) {
+	cmd := m.shInput.Focus()
+	return m, cmd
+}
+
+func (m model) viewShell() string {
+	// as much of the end of the scrollback as fits, long lines wrapped
+	var rows []string
+	for i := len(m.shLines) - 1; i >= 0 && len(rows) < shellLines; i-- {
+		wrapped := strings.Split(m.styles.wrap.Render(m.shLines[i]), "\n")
+		rows = append(wrapped, rows...)
+	}
+	if len(rows) > shellLines {
+		rows = rows[len(rows)-shellLines:]
+	}
+	for len(rows) < shellLines {
+		rows = append(rows, "")
+	}
+
+	prompt := m.shInput.View()
+	if !m.shInput.Focused() {
+		prompt = m.styles.meta.Render(m.shInput.Prompt + "  (enter or i: type a command)")
+	}
+	return strings.Join(append(rows, prompt), "\n")
+}
+
+func (m model) a11yShell(add func(...string)) {
+	add("Shell", "")
+	for _, line := range m.shLines[max(0, len(m.shLines)-shellLines):] {
+		add(line)
+	}
+	add("")
+	if m.shInput.Focused() {
+		add("Command: " + m.shInput.Prompt + m.shInput.Value())
+		add("Type help for the commands. Press tab to complete, escape to leave the prompt.")
+	} else {
+		add("Press enter or i to type a command.")
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// update feeds m each message in turn, the way the program would.
+func update(m model, msgs ...tea.Msg) model {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	return m
+}
+
+// Landing on the Shell tab, on purpose or by wrapping around, must leave the
+// usual keys working; the prompt only takes them when asked for.
+func TestShellTabKeepsKeys(t *testing.T) {
+	p := loadFixture(t, "full")
+	for _, tc := range []struct {
+		name string
+		keys []tea.Msg
+	}{
+		{"wrap", press("h")},
+		{"jump", press("5")},
+		{"step", press("4", "l")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := update(newTestModel(t, p, Options{SkipIntro: true}), tc.keys...)
+			if m.activeTab != shellTab {
+				t.Fatalf("on %s, want Shell", tabLabels[m.activeTab])
+			}
+			if m.shInput.Focused() {
+				t.Fatal("arriving on the tab focused the prompt")
+			}
+			if m = update(m, press("q")...); !m.quitting {
+				t.Error("q didn't quit")
+			}
+		})
+	}
+
+	for _, key := range []string{"enter", "i"} {
+		t.Run(key, func(t *testing.T) {
+			m := update(newTestModel(t, p, Options{SkipIntro: true}), press("5", key, "q")...)
+			if m.quitting || m.shInput.Value() != "q" {
+				t.Fatalf("after %s, q quit %t and the prompt reads %q", key, m.quitting, m.shInput.Value())
+			}
+			if m = update(m, press("esc", "q")...); !m.quitting {
+				t.Error("q didn't quit after esc put the prompt down")
+			}
+		})
+	}
+}
@@ -5,68 +5,140 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// The palette. Each color has a variant for light and dark terminal
+// backgrounds; the renderer picks one from the background it detected, or
+// from the visitor's choice once they toggle it.
 var (
-	nameStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FFD7FF"))
+	colorName     = lipgloss.AdaptiveColor{Light: "#AF005F", Dark: "#FFD7FF"}
+	colorAccent   = lipgloss.AdaptiveColor{Light: "#D7005F", Dark: "#FF5F87"}
+	colorDot      = lipgloss.AdaptiveColor{Light: "#D7005F", Dark: "#FF75B7"}
+	colorTagline  = lipgloss.AdaptiveColor{Light: "#3A3A3A", Dark: "#DDDDDD"}
+	colorMeta     = lipgloss.AdaptiveColor{Light: "#5F5F5F", Dark: "#AAAAAA"}
+	colorInactive = lipgloss.AdaptiveColor{Light: "#6C6C6C", Dark: "#888888"}
+	colorDim      = lipgloss.AdaptiveColor{Light: "#767676", Dark: "#555555"}
+	colorDotDim   = lipgloss.AdaptiveColor{Light: "#BCBCBC", Dark: "#555555"}
+	colorTabText  = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"}
+)
+
+// styles are built per model from the session's renderer, so colors are
+// downsampled to what the visitor's terminal can show. Nothing in the UI
+// should render through lipgloss' default renderer: that one describes the
+// server's stdout, not the visitor.
+type styles struct {
+	name         lipgloss.Style
+	introName    lipgloss.Style // the name as the intro draws it, which intro: may recolor
+	tagline      lipgloss.Style
+	header       lipgloss.Style // experience / project titles
+	meta         lipgloss.Style // period, location
+	cursor       lipgloss.Style
+	card         lipgloss.Style
+	content      lipgloss.Style
+	wrap         lipgloss.Style // free text, wrapped to the content width
+	tabActive    lipgloss.Style
+	tabInactive  lipgloss.Style
+	tabsRow      lipgloss.Style
+	footer       lipgloss.Style
+	warning      lipgloss.Style
+	toast        lipgloss.Style
+	contactTitle lipgloss.Style
+	dotActive    lipgloss.Style
+	dotInactive  lipgloss.Style
+}
+
+func newStyles(r *lipgloss.Renderer, g glyphs) styles {
+	name := r.NewStyle().
+		Bold(true).
+		Foreground(colorName)
+	return styles{
+		name:      name,
+		introName: name,
+
+		// slightly dimmer than the name
+		tagline: r.NewStyle().
+			Foreground(colorTagline),
 
-	// Thick, colored cursor
-	cursorStyle = lipgloss.NewStyle().
+		header: r.NewStyle().
+			Bold(true),
+
+		meta: r.NewStyle().
+			Foreground(colorMeta),
+
+		// Thick, colored cursor
+		cursor: r.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FF5F87"))
+			Foreground(colorAccent),
 
-	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
+		card: r.NewStyle().
+			Border(g.border).
 			Padding(1, 2).
 			Width(appWidth).
-			Height(appHeight)
+			Height(appHeight),
 
-	contentStyle = lipgloss.NewStyle().
+		content: r.NewStyle().
 			Width(appWidth - 4). // inside padding
-			Height(appHeight - 5)
+			Height(appHeight - 5),
+
+		wrap: r.NewStyle().
+			Width(appWidth - 4),
 
-	tabActiveStyle = lipgloss.NewStyle().
+		tabActive: r.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#000000")).
-			Background(lipgloss.Color("#FFD7FF")).
-			Padding(0, 1)
+			Foreground(colorTabText).
+			Background(colorName).
+			Padding(0, 1),
 
-	tabInactiveStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#888888")).
-				Padding(0, 1)
+		tabInactive: r.NewStyle().
+			Foreground(colorInactive).
+			Padding(0, 1),
 
-	tabsRowStyle = lipgloss.NewStyle().
+		tabsRow: r.NewStyle().
 			Width(appWidth).
-			Align(lipgloss.Center)
+			Align(lipgloss.Center),
 
-	footerStyle = lipgloss.NewStyle().
+		footer: r.NewStyle().
 			Width(appWidth).
-			Foreground(lipgloss.Color("#555555")).
-			Align(lipgloss.Center)
-	contactTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFD7FF")). // soft pink, same vibe as tabs
-				MarginBottom(1).
-				Underline(false)
-)
+			Foreground(colorDim).
+			Align(lipgloss.Center),
 
-func newPaginator(total int) paginator.Model {
+		warning: r.NewStyle().
+			Width(appWidth).
+			Bold(true).
+			Foreground(colorAccent).
+			Align(lipgloss.Center),
+
+		toast: r.NewStyle().
+			Width(appWidth).
+			Foreground(colorAccent).
+			Align(lipgloss.Center),
+
+		contactTitle: r.NewStyle().
+			Bold(true).
+			Foreground(colorName). // soft pink, same vibe as tabs
+			MarginBottom(1).
+			Underline(false),
+
+		dotActive: r.NewStyle().
+			Foreground(colorDot). // pink
+			Bold(true),
+
+		dotInactive: r.NewStyle().
+			Foreground(colorDotDim), // dim gray
+	}
+}
+
+func newPaginator(total int, s styles, g glyphs) paginator.Model {
 	p := paginator.New()
 	p.PerPage = 1
 	p.TotalPages = total
 	p.Type = paginator.Dots
 
-	active := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF75B7")). // pink
-		Bold(true).
-		Render("●")
-
-	inactive := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#555555")). // dim gray
-		Render("•")
-
-	p.ActiveDot = active
-	p.InactiveDot = inactive
+	return paintDots(p, s, g)
+}
 
+// paintDots renders the paginator's dots, which it keeps as plain strings,
+// so they need redoing whenever the theme changes.
+func paintDots(p paginator.Model, s styles, g glyphs) paginator.Model {
+	p.ActiveDot = s.dotActive.Render(g.activeDot)
+	p.InactiveDot = s.dotInactive.Render(g.inactiveDot)
 	return p
 }
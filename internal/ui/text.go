@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// grapheme is one user-perceived character and the number of terminal
+// cells it takes up: 2 for CJK and most emoji, 0 for a newline.
+type grapheme struct {
+	text  string
+	width int
+}
+
+// splitGraphemes breaks s into grapheme clusters, so an accented letter,
+// a Devanagari conjunct or a flag is never cut in half.
+func splitGraphemes(s string) []grapheme {
+	var out []grapheme
+	state := -1
+	for s != "" {
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if cluster == "\n" || cluster == "\r\n" {
+			width = 0
+		}
+		out = append(out, grapheme{text: cluster, width: width})
+	}
+	return out
+}
+
+// joinGraphemes is the text of gs.
+func joinGraphemes(gs []grapheme) string {
+	var b strings.Builder
+	for _, g := range gs {
+		b.WriteString(g.text)
+	}
+	return b.String()
+}
+
+// blank reports whether g is whitespace, which effects leave alone.
+func (g grapheme) blank() bool {
+	return strings.TrimSpace(g.text) == ""
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// wideNames are names an intro has to draw without cutting a character in
+// half or losing track of how wide the line is.
+var wideNames = []string{
+	"Zoë Ñúñez",
+	"José García", // combining accents, not precomposed
+	"李小龍",
+	"김민지 · Kim Minji",
+	"Priya क्षमा",
+	"Renée 🇮🇳 👩‍💻",
+	"山田 太郎\nソフトウェアエンジニア",
+}
+
+func TestSplitGraphemes(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		want   []string
+		widths []int
+	}{
+		{"abc", []string{"a", "b", "c"}, []int{1, 1, 1}},
+		{"é", []string{"é"}, []int{1}},
+		{"李小", []string{"李", "小"}, []int{2, 2}},
+		{"🇮🇳", []string{"🇮🇳"}, []int{2}},
+		{"👩‍💻", []string{"👩‍💻"}, []int{2}},
+		{"a\nb", []string{"a", "\n", "b"}, []int{1, 0, 1}},
+	} {
+		gs := splitGraphemes(tc.in)
+		if len(gs) != len(tc.want) {
+			t.Errorf("splitGraphemes(%q) = %d graphemes, want %d", tc.in, len(gs), len(tc.want))
+			continue
+		}
+		for i, g := range gs {
+			if g.text != tc.want[i] || g.width != tc.widths[i] {
+				t.Errorf("splitGraphemes(%q)[%d] = %q (%d cells), want %q (%d cells)",
+					tc.in, i, g.text, g.width, tc.want[i], tc.widths[i])
+			}
+		}
+		if got := joinGraphemes(gs); got != tc.in {
+			t.Errorf("joinGraphemes(splitGraphemes(%q)) = %q", tc.in, got)
+		}
+	}
+}
+
+// cells is how wide the widest line of s is.
+func cells(s string) int {
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		width = max(width, ansi.StringWidth(line))
+	}
+	return width
+}
+
+func TestEffectsWideNames(t *testing.T) {
+	for _, name := range wideNames {
+		for effect := range introEffects {
+			t.Run(effect+"/"+name, func(t *testing.T) {
+				e := testEffect(effect, name, termenv.TrueColor)
+				end := lastFrame(t, e)
+				for n := 0; n <= end; n++ {
+					frame, _ := e.frame(n)
+					if !utf8.ValidString(frame) {
+						t.Fatalf("frame %d is not valid UTF-8: %q", n, frame)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestTypewriterWideNames checks the typewriter only ever adds whole
+// characters: each frame is a prefix of the name, and never narrower than
+// the one before.
+func TestTypewriterWideNames(t *testing.T) {
+	for _, name := range wideNames {
+		t.Run(name, func(t *testing.T) {
+			e := testEffect("typewriter", name, termenv.TrueColor)
+			end := lastFrame(t, e)
+			prev := 0
+			for n := 0; n <= end; n++ {
+				frame, _ := e.frame(n)
+				typed := unpad(strings.TrimSuffix(ansi.Strip(frame), unicodeGlyphs.cursor))
+				if !strings.HasPrefix(name, typed) {
+					t.Fatalf("frame %d shows %q, not a prefix of the name", n, typed)
+				}
+				if !startsGrapheme(name, len(typed)) {
+					t.Fatalf("frame %d cuts a character in half: %q", n, typed)
+				}
+				width := cells(typed)
+				if width < prev {
+					t.Fatalf("frame %d is %d cells wide, narrower than the %d before", n, width, prev)
+				}
+				prev = width
+			}
+			final, _ := e.frame(end)
+			if got := unpad(strings.TrimSuffix(ansi.Strip(final), unicodeGlyphs.cursor)); got != name {
+				t.Errorf("last frame = %q, want %q", got, name)
+			}
+		})
+	}
+}
+
+// unpad drops the spaces lipgloss pads short lines of a block with.
+func unpad(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startsGrapheme reports whether a grapheme cluster of s starts at byte i.
+func startsGrapheme(s string, i int) bool {
+	at := 0
+	for _, g := range splitGraphemes(s) {
+		if at == i {
+			return true
+		}
+		at += len(g.text)
+	}
+	return at == i
+}
+
+// TestDecryptWideNames checks that scrambling keeps every line as wide as
+// the name it stands in for, so the centered text doesn't jitter, and that
+// the last frame is the name itself.
+func TestDecryptWideNames(t *testing.T) {
+	for _, name := range wideNames {
+		t.Run(name, func(t *testing.T) {
+			e := testEffect("decrypt", name, termenv.TrueColor)
+			wantLines := strings.Split(name, "\n")
+			end := lastFrame(t, e)
+			for n := 0; n <= end; n++ {
+				frame, _ := e.frame(n)
+				lines := strings.Split(ansi.Strip(frame), "\n")
+				if len(lines) != len(wantLines) {
+					t.Fatalf("frame %d has %d lines, want %d", n, len(lines), len(wantLines))
+				}
+				for i, line := range lines {
+					if got, want := ansi.StringWidth(line), ansi.StringWidth(wantLines[i]); got != want {
+						t.Fatalf("frame %d line %d is %d cells wide, want %d: %q", n, i, got, want, line)
+					}
+				}
+			}
+			final, _ := e.frame(end)
+			if got := ansi.Strip(final); got != name {
+				t.Errorf("last frame = %q, want %q", got, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
+)
+
+// The SGR foreground for colorName in each variant.
+const (
+	nameDark  = "38;2;255;215;255" // #FFD7FF
+	nameLight = "38;2;175;0;95"    // #AF005F
+)
+
+func TestThemeVariants(t *testing.T) {
+	p := loadFixture(t, "full")
+	for _, tc := range []struct {
+		name      string
+		dark      bool
+		want, not string
+	}{
+		{"dark", true, nameDark, nameLight},
+		{"light", false, nameLight, nameDark},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestModel(t, p, Options{SkipIntro: true, Renderer: testRenderer(termenv.TrueColor, tc.dark)})
+			m = drive(t, m, 120, 40)
+			out := m.View()
+			if !strings.Contains(out, tc.want) {
+				t.Errorf("name isn't drawn in %s", tc.want)
+			}
+			if strings.Contains(out, tc.not) {
+				t.Errorf("%s frame uses the other variant's %s", tc.name, tc.not)
+			}
+			golden.RequireEqual(t, []byte(out))
+		})
+	}
+}
+
+// TestThemeToggle checks that t redraws in the other palette, pager dots
+// included, and that the view cache doesn't hand back the old frame.
+func TestThemeToggle(t *testing.T) {
+	p := loadFixture(t, "full")
+
+	light := newTestModel(t, p, Options{SkipIntro: true, Renderer: testRenderer(termenv.TrueColor, false)})
+	light = drive(t, light, 120, 40, press("2")...)
+	want := light.View()
+
+	m := newTestModel(t, p, Options{SkipIntro: true, Renderer: testRenderer(termenv.TrueColor, true)})
+	m = drive(t, m, 120, 40, press("2")...)
+	dark := m.View() // cached under the dark key
+	m = drive(t, m, 120, 40, press("t")...)
+	if got := m.View(); got != want {
+		t.Errorf("after t, frame differs from a light-started session:\n%s", got)
+	}
+	m = drive(t, m, 120, 40, press("t")...)
+	if got := m.View(); got != dark {
+		t.Errorf("toggling twice doesn't return to the dark frame:\n%s", got)
+	}
+}
+
+// withIntro is p with its intro: block replaced.
+func withIntro(p *portfolio.Snapshot, intro portfolio.Intro) *portfolio.Snapshot {
+	pf := *p.Portfolio
+	pf.Intro = intro
+	return &portfolio.Snapshot{Portfolio: &pf, Version: p.Version}
+}
+
+// TestIntroColor checks that intro.color only recolors the intro: the card's
+// name still follows the theme, and t still changes it.
+func TestIntroColor(t *testing.T) {
+	const green = "38;2;0;255;0"
+	p := withIntro(loadFixture(t, "full"), portfolio.Intro{Color: "#00FF00"})
+
+	m := newTestModel(t, p, Options{Renderer: testRenderer(termenv.TrueColor, true)})
+	if frame, _ := m.intro.frame(lastFrame(t, m.intro)); !strings.Contains(frame, green) {
+		t.Error("the intro doesn't use intro.color")
+	}
+
+	m = drive(t, m.skipIntro(), 120, 40)
+	if out := m.View(); !strings.Contains(out, nameDark) || strings.Contains(out, green) {
+		t.Error("the card's name took intro.color instead of the theme's")
+	}
+	m = drive(t, m, 120, 40, press("t")...)
+	if out := m.View(); !strings.Contains(out, nameLight) {
+		t.Error("t didn't recolor the name")
+	}
+}
+
+func TestIntroCursorASCII(t *testing.T) {
+	p := loadFixture(t, "full")
+	for _, tc := range []struct {
+		cursor string
+		ascii  bool
+		want   string
+	}{
+		{"▌", false, "▌"},
+		{"▌", true, asciiGlyphs.cursor},
+		{"|", true, "|"},
+	} {
+		m := newTestModel(t, withIntro(p, portfolio.Intro{Cursor: tc.cursor}), Options{ASCII: tc.ascii})
+		if m.glyphs.cursor != tc.want {
+			t.Errorf("cursor %q with ASCII %t = %q, want %q", tc.cursor, tc.ascii, m.glyphs.cursor, tc.want)
+		}
+	}
+}
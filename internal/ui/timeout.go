@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// warnBefore is how long before a timeout the visitor gets the countdown.
+const warnBefore = 30 * time.Second
+
+type clockMsg time.Time
+
+func clockCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return clockMsg(t)
+	})
+}
+
+// checkTimeouts ends the session once it has been idle or alive for too long,
+// and sets the countdown warning shortly before either happens.
+func (m model) checkTimeouts(now time.Time) (model, tea.Cmd) {
+	m.timeoutWarning = ""
+	m.idleWarning = false
+
+	if m.maxTimeout > 0 {
+		left := m.started.Add(m.maxTimeout).Sub(now)
+		if left <= 0 {
+			return m.timedOut("max", "Session time limit reached.", now)
+		}
+		if left <= warnBefore {
+			m.timeoutWarning = fmt.Sprintf("Session time limit: closing in %ds.", secondsLeft(left))
+		}
+	}
+
+	if m.idleTimeout > 0 && m.timeoutWarning == "" {
+		left := m.lastInput.Add(m.idleTimeout).Sub(now)
+		if left <= 0 {
+			return m.timedOut("idle", fmt.Sprintf("Disconnected after %s without input.", m.idleTimeout), now)
+		}
+		if left <= warnBefore {
+			m.timeoutWarning = fmt.Sprintf("Still there? Closing in %ds, press any key to stay.", secondsLeft(left))
+			m.idleWarning = true
+		}
+	}
+
+	return m, clockCmd()
+}
+
+func (m model) timedOut(kind, reason string, now time.Time) (model, tea.Cmd) {
+	m.quitting = true
+	m.quitReason = reason
+	m.trackQuit(kind, now)
+	return m, tea.Quit
+}
+
+func secondsLeft(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
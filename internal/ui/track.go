@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/analytics"
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
+)
+
+// trackNav emits a tab_view for the tab the visitor just left and a
+// project_view whenever a project comes on screen.
+func (m model) trackNav(prevTab, prevProject int, now time.Time) model {
+	if m.activeTab != prevTab {
+		m.events.Emit(analytics.Event{
+			Kind:     analytics.KindTabView,
+			Tab:      tabLabels[prevTab],
+			Duration: now.Sub(m.tabSince).Seconds(),
+		})
+		m.tabSince = now
+		metrics.TabViews.WithLabelValues(tabLabels[m.activeTab]).Inc()
+	}
+
+	if m.activeTab == projectsTab && len(m.portfolio.Projects) > 0 &&
+		(m.activeTab != prevTab || m.projList.Page != prevProject) {
+		m.events.Emit(analytics.Event{
+			Kind:    analytics.KindProjectView,
+			Project: m.portfolio.Projects[m.projList.Page].Name,
+		})
+	}
+
+	return m
+}
+
+// trackSearch records a grep run in the shell.
+func (m model) trackSearch(query string) {
+	m.events.Emit(analytics.Event{
+		Kind:  analytics.KindSearch,
+		Query: query,
+	})
+}
+
+// trackQuit closes out the current tab and records why the session ended.
+func (m model) trackQuit(reason string, now time.Time) {
+	m.events.Emit(analytics.Event{
+		Kind:     analytics.KindTabView,
+		Tab:      tabLabels[m.activeTab],
+		Duration: now.Sub(m.tabSince).Seconds(),
+	})
+	m.events.Emit(analytics.Event{
+		Kind:     analytics.KindQuit,
+		Reason:   reason,
+		Duration: now.Sub(m.started).Seconds(),
+	})
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shbhom/ssh-portfolio/internal/portfolio"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+)
+
+// loadFixture reads testdata/fixtures/<name>.yaml.
+func loadFixture(t testing.TB, name string) *portfolio.Snapshot {
+	t.Helper()
+	store, err := portfolio.Open(filepath.Join("testdata", "fixtures", name+".yaml"))
+	if err != nil {
+		t.Fatalf("loading fixture %s: %v", name, err)
+	}
+	return store.Current()
+}
+
+// testRenderer stands in for a visitor's terminal. The Ascii profile keeps
+// golden files free of color codes, so they read as the layout they are.
+func testRenderer(profile termenv.Profile, dark bool) *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(profile)
+	r.SetHasDarkBackground(dark)
+	return r
+}
+
+// newTestModel builds a model the way a session would, with a fixed seed.
+func newTestModel(t testing.TB, p *portfolio.Snapshot, opts Options) model {
+	t.Helper()
+	if opts.Renderer == nil {
+		opts.Renderer = testRenderer(termenv.Ascii, true)
+	}
+	if opts.Seed == 0 {
+		opts.Seed = 1
+	}
+	return NewModel("visitor", p, opts)
+}
+
+// drive runs m in a program sized width x height (unsized for 0), sends
+// msgs in order and returns the model once they have all been handled.
+func drive(t *testing.T, m model, width, height int, msgs ...tea.Msg) model {
+	t.Helper()
+	var opts []teatest.TestOption
+	if width > 0 {
+		opts = append(opts, teatest.WithInitialTermSize(width, height))
+	}
+	tm := teatest.NewTestModel(t, m, opts...)
+	for _, msg := range msgs {
+		tm.Send(msg)
+	}
+	_ = tm.Quit() // queued behind msgs
+	return tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+}
+
+// press turns each string into the key it names: "enter", "esc", "tab", or
+// a single character.
+func press(names ...string) []tea.Msg {
+	msgs := make([]tea.Msg, len(names))
+	for i, name := range names {
+		switch name {
+		case "enter":
+			msgs[i] = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msgs[i] = tea.KeyMsg{Type: tea.KeyEsc}
+		case "tab":
+			msgs[i] = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msgs[i] = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+		}
+	}
+	return msgs
+}
+
+// typed is the keys for typing s.
+func typed(s string) []tea.Msg {
+	msgs := make([]tea.Msg, 0, len(s))
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
@@ -9,7 +9,35 @@ import (
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.activeTab == 1 { // assuming 0=Overview, 1=Experience
+		now := time.Now()
+		m.lastInput = now
+		if m.idleWarning {
+			// the key only cancels the countdown
+			m.idleWarning = false
+			m.timeoutWarning = ""
+			return m, nil
+		}
+		if m.loading && msg.String() != "ctrl+c" {
+			// any key skips the intro, and does nothing else
+			return m.skipIntro(), nil
+		}
+		if m.gbInput.Focused() && msg.String() != "ctrl+c" {
+			// typing a guestbook message, keys are text
+			return m.updateGuestbookInput(msg, now)
+		}
+		if m.form.open && msg.String() != "ctrl+c" {
+			return m.updateContactForm(msg, now)
+		}
+		if m.qr != nil && msg.String() != "ctrl+c" {
+			return m.updateQR(msg)
+		}
+		if m.shInput.Focused() && msg.String() != "ctrl+c" {
+			return m.updateShellInput(msg)
+		}
+
+		prevTab, prevProject := m.activeTab, m.projList.Page
+
+		if m.activeTab == experienceTab {
 			switch msg.String() {
 			case "j", "down":
 				m.expList.NextPage()
@@ -17,7 +45,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.expList.PrevPage()
 			}
 		}
-		if m.activeTab == 2 { // assuming 0=Overview, 1=Experience
+		if m.activeTab == projectsTab {
 			switch msg.String() {
 			case "j", "down":
 				m.projList.NextPage()
@@ -25,94 +53,109 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.projList.PrevPage()
 			}
 		}
+		if m.activeTab == contactTab {
+			switch msg.String() {
+			case "j", "down":
+				m = m.moveCopySel(1)
+			case "k", "up":
+				m = m.moveCopySel(-1)
+			}
+		}
 		switch msg.String() {
-		case "1":
-			m.activeTab = 0
-		case "2":
-			m.activeTab = 1
-		case "3":
-			m.activeTab = 2
-		case "4":
-			m.activeTab = 3
+		case "1", "2", "3", "4", "5", "6":
+			if n := int(msg.String()[0] - '1'); n < len(m.tabs()) {
+				m.activeTab = m.tabs()[n]
+			}
 
 		case "left", "h":
-			if m.activeTab == 0 {
-				m.activeTab = 3
-			} else {
-				m.activeTab--
-			}
+			m = m.moveTab(-1)
 		case "right", "l":
-			if m.activeTab == 3 {
-				m.activeTab = 0
-			} else {
-				m.activeTab++
+			m = m.moveTab(1)
+		case "w", "enter":
+			if m.activeTab == guestbookTab {
+				return m.startGuestbookInput()
+			}
+			if m.activeTab == shellTab && msg.String() == "enter" {
+				return m.focusShell()
+			}
+		case "i":
+			if m.activeTab == shellTab {
+				return m.focusShell()
+			}
+		case "m":
+			if m.activeTab == contactTab && m.contact != nil {
+				return m.openContactForm()
 			}
-		case "?":
-			m.help.ShowAll = !m.help.ShowAll
+		case "tab":
+			m = m.moveCopySel(1)
+		case "shift+tab":
+			m = m.moveCopySel(-1)
+		case "c":
+			return m.copySelected()
+		case "o":
+			return m.openQR()
+		case "t":
+			m = m.toggleTheme()
+		case "a":
+			m = m.setAccessible(!m.accessible)
 		case "q", "esc", "ctrl+c":
 			m.quitting = true
+			m.trackQuit("key", now)
 			return m, tea.Quit
 		}
+
+		if m.activeTab != prevTab || m.projList.Page != prevProject {
+			m.copySel = 0
+		}
+		m = m.trackNav(prevTab, prevProject, now)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width // 👈 store
 		m.height = msg.Height
 		return m, nil
 
+	case toastMsg:
+		if int(msg) == m.toastSeq {
+			m.toast = ""
+		}
+		return m, nil
+
+	case clockMsg:
+		return m.checkTimeouts(time.Time(msg))
+
 	case tickMsg:
 		// If loading is already over, ignore further ticks
 		if !m.loading {
 			return m, nil
 		}
 
-		m.frameCount++
-
-		switch m.phase {
-		case 0:
-			// Phase 0: blink-only cursor for a bit (~1.5s)
-			if m.frameCount%3 == 0 {
-				m.cursorOn = !m.cursorOn
-			}
-			if m.frameCount >= 20 {
-				m.phase = 1
-				m.frameCount = 0
-			}
-
-		case 1:
-			// Phase 1: typing the name
-			if m.frameCount%3 == 0 {
-				m.cursorOn = !m.cursorOn
-			}
-
-			if m.frameCount%2 == 0 && m.typedChars < len(m.introText) {
-				m.typedChars++
-			}
-
-			if m.typedChars >= len(m.introText) {
-				// ✅ DO *NOT* set loading = false here
-				// just move to pause phase
-				m.phase = 2
-				m.frameCount = 0
-				m.cursorOn = true // keep cursor visible at end of name
-			}
-
-		case 2:
-			// Phase 2: pause with full name visible
-			if m.frameCount >= pauseTicks {
-				// Now we finally end loading and switch to normal mode
-				m.loading = false
-				m.cursorOn = false // optional: hide cursor in normal mode
-				return m, nil      // stop scheduling ticks
+		m.introFrame++
+		if m.introDoneAt < 0 {
+			if _, done := m.intro.frame(m.introFrame); done {
+				m.introDoneAt = m.introFrame
 			}
+		} else if m.introFrame-m.introDoneAt >= m.pauseTicks {
+			// the finished frame has been up long enough, show the card
+			m.loading = false
+			return m, nil // stop scheduling ticks
 		}
 
-		// Keep animation running for phases 0, 1, and 2
-		return m, tickCmd()
+		// Keep the animation running
+		return m, tickCmd(m.tick)
 	}
 	return m, nil
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(60*time.Millisecond, func(t time.Time) tea.Msg {
+// toggleTheme flips between the light and dark palette, for terminals whose
+// background we guessed wrong or couldn't query at all.
+func (m model) toggleTheme() model {
+	m.renderer.SetHasDarkBackground(!m.renderer.HasDarkBackground())
+	m.expList = paintDots(m.expList, m.styles, m.glyphs)
+	m.projList = paintDots(m.projList, m.styles, m.glyphs)
+	return m
+}
+
+func tickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -2,114 +2,179 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/Shbhom/ssh-portfolio/internal/metrics"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Tabs with a page of their own in every session. contactTab, guestbookTab
+// and shellTab live with the code for their tabs.
+const (
+	overviewTab   = 0
+	experienceTab = 1
+	projectsTab   = 2
+)
+
+// tabLabels is indexed by tab: activeTab is always one of these, whatever
+// position the tab has in this session's row.
+var tabLabels = []string{"Overview", "Experience", "Projects", "Contact", "Guestbook", "Shell"}
+
+// tabs is this session's tabs, in order; the guestbook tab only exists
+// when the server keeps a guestbook.
+func (m model) tabs() []int {
+	tabs := []int{overviewTab, experienceTab, projectsTab, contactTab}
+	if m.guestbook != nil {
+		tabs = append(tabs, guestbookTab)
+	}
+	return append(tabs, shellTab)
+}
+
+// moveTab switches to the tab delta places along the row, wrapping around.
+func (m model) moveTab(delta int) model {
+	tabs := m.tabs()
+	i := slices.Index(tabs, m.activeTab)
+	m.activeTab = tabs[((i+delta)%len(tabs)+len(tabs))%len(tabs)]
+	return m
+}
+
 func (m model) viewTabs() string {
-	labels := []string{"Overview", "Experience", "Projects", "Contact"}
 	var rendered []string
 
-	for i, label := range labels {
-		if i == m.activeTab {
-			rendered = append(rendered, tabActiveStyle.Render(label))
+	for _, tab := range m.tabs() {
+		label := tabLabels[tab]
+		if tab == m.activeTab {
+			rendered = append(rendered, m.styles.tabActive.Render(label))
 		} else {
-			rendered = append(rendered, tabInactiveStyle.Render(label))
+			rendered = append(rendered, m.styles.tabInactive.Render(label))
 		}
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Left, rendered...)
-	return tabsRowStyle.Render(row)
+	return m.styles.tabsRow.Render(row)
 }
 
 func (m model) viewTabContent() string {
 	var text string
 
 	switch m.activeTab {
-	case 0:
-		text = contentStyle.Render(m.viewOverview())
-	case 1:
-		text = contentStyle.Render(m.viewExperience())
-	case 2:
-		text = contentStyle.Render(m.viewProjects())
-	case 3:
-		text = contentStyle.Render(m.viewContact())
+	case overviewTab:
+		text = m.styles.content.Render(m.viewOverview())
+	case experienceTab:
+		text = m.styles.content.Render(m.viewExperience())
+	case projectsTab:
+		text = m.styles.content.Render(m.viewProjects())
+	case contactTab:
+		text = m.styles.content.Render(m.viewContact())
+	case guestbookTab:
+		text = m.styles.content.Render(m.viewGuestbook())
+	case shellTab:
+		text = m.styles.content.Render(m.viewShell())
 	}
 
-	return contentStyle.Render(text)
+	return m.styles.content.Render(text)
 }
 
 func (m model) viewFooter() string {
-	helpLine := "h/← & l/→: switch tabs  •  1–4: jump to tab  •  q: quit"
-	switch m.activeTab {
-	case 1:
-		helpLine += "  •  j/k or ↑/↓: switch experience"
-	case 2:
-		helpLine += "  •  j/k or ↑/↓: switch project"
+	footer := m.viewFooterLine()
+	if m.recording {
+		// the MOTD said so too, but the alt screen covers it straight away
+		rec := m.styles.toast.Render(m.glyphs.rec + " REC: this session is being recorded")
+		return lipgloss.JoinVertical(lipgloss.Left, rec, footer)
 	}
-	return footerStyle.Render(helpLine)
+	return footer
 }
 
-func (m model) View() string {
-	if m.quitting {
-		return "Bye!\n"
+// viewFooterLine is the key help, or a warning or toast in its place.
+func (m model) viewFooterLine() string {
+	if m.timeoutWarning != "" {
+		return m.styles.warning.Render(m.timeoutWarning)
+	}
+	if m.toast != "" {
+		return m.styles.toast.Render(m.toast)
 	}
 
-	var content string
+	g := m.glyphs
+	if m.activeTab == shellTab && m.shInput.Focused() {
+		// the usual keys are text while the prompt has focus
+		return m.styles.footer.Render(fmt.Sprintf("tab: complete  %s  %s/%s: history  %s  esc: leave the prompt  %s  ctrl+c: quit",
+			g.bullet, g.up, g.down, g.bullet, g.bullet))
+	}
+	helpLine := fmt.Sprintf("h/%s & l/%s: tabs  %s  1%s%d: jump  %s  t: theme  %s  a: a11y  %s  q: quit",
+		g.left, g.right, g.bullet, g.enDash, len(m.tabs()), g.bullet, g.bullet, g.bullet)
+	switch m.activeTab {
+	case experienceTab:
+		helpLine += fmt.Sprintf("  %s  j/k or %s/%s: experiences", g.bullet, g.up, g.down)
+	case projectsTab:
+		helpLine += fmt.Sprintf("  %s  j/k or %s/%s: projects", g.bullet, g.up, g.down)
+	}
+	return m.styles.footer.Render(helpLine)
+}
 
-	if m.loading {
-		// Intro phase: typewriter animation for "Shubhom Srivastava"
+func (m model) View() string {
+	defer metrics.ObserveRender(time.Now())
 
-		if m.typedChars < 0 {
-			m.typedChars = 0
-		}
-		if m.typedChars > len(m.introText) {
-			m.typedChars = len(m.introText)
+	if m.quitting {
+		if m.quitReason != "" {
+			return m.quitReason + " Bye!\n"
 		}
+		return "Bye!\n"
+	}
 
-		visible := m.introText[:m.typedChars]
-
-		// Thicker cursor: full block "█"
-		cursorChar := ""
-		if m.cursorOn {
-			cursorChar = "█"
-		}
+	if m.qr != nil {
+		// before accessible mode: the code is for the phone, not the reader
+		return m.viewQR()
+	}
 
-		// Style the name + cursor separately
-		styledName := m.nameStyle.Render(visible)
-		styledCursor := ""
-		if cursorChar != "" {
-			styledCursor = m.cursorStyle.Render(cursorChar)
-		}
+	if m.accessible {
+		return m.viewAccessible()
+	}
 
-		line := styledName + styledCursor
-		content = line
-	} else {
-		// 🔹 Main portfolio card view
+	if m.loading {
+		// Intro phase: whichever effect the intro: block picked
+		content, _ := m.intro.frame(m.introFrame)
+		return m.place(content)
+	}
 
-		tabContent := m.viewTabContent()
-		tabsRow := m.viewTabs()
-		footer := m.viewFooter()
+	// 🔹 Main portfolio card view
+	if m.timeoutWarning != "" || m.toast != "" || m.activeTab == guestbookTab || m.activeTab == shellTab ||
+		(m.activeTab == contactTab && (m.form.open || m.form.status != "")) {
+		// the countdown changes every second, toasts come and go, and the
+		// guestbook, shell and contact form change with every keystroke:
+		// not worth caching
+		return m.viewCard()
+	}
+	return m.cards.get(m.cardKey(), m.viewCard)
+}
 
-		body := lipgloss.JoinVertical(
-			lipgloss.Left,
-			tabContent,
-			"",
-			tabsRow,
-			footer,
-		)
+// viewCard renders the card centered in the window.
+func (m model) viewCard() string {
+	tabContent := m.viewTabContent()
+	tabsRow := m.viewTabs()
+	footer := m.viewFooter()
+
+	body := lipgloss.JoinVertical(
+		lipgloss.Left,
+		tabContent,
+		"",
+		tabsRow,
+		footer,
+	)
 
-		content = cardStyle.Render(body)
-	}
+	return m.place(m.styles.card.Render(body))
+}
 
+// place centers content in the window.
+func (m model) place(content string) string {
 	// If we don't know the size yet, just return the raw content.
 	if m.width == 0 || m.height == 0 {
 		return content + "\n"
 	}
 
 	// Center the content (intro or card) in the available space
-	return lipgloss.Place(
+	return m.renderer.Place(
 		m.width,
 		m.height,
 		lipgloss.Center, // horizontal
@@ -131,9 +196,9 @@ func termLink(label, url string) string {
 	return esc + "]8;;" + url + bel + label + esc + "]8;;" + bel
 }
 
-func centerInContent(s string) string {
+func (m model) centerInContent(s string) string {
 	innerWidth := appWidth - 4 // same width you use in contentStyle
-	return lipgloss.PlaceHorizontal(innerWidth, lipgloss.Center, s)
+	return m.renderer.PlaceHorizontal(innerWidth, lipgloss.Center, s)
 }
 
 func (m model) viewOverview() string {
@@ -145,15 +210,11 @@ func (m model) viewOverview() string {
 
 	var lines []string
 
-	// 1) Name
-	nameLine := centerInContent(m.nameStyle.Render(p.Name))
-	lines = append(lines, nameLine)
-
-	// 2) Tagline (slightly dimmer / separate style if you want)
-	taglineStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#DDDDDD"))
-	taglineLine := centerInContent(taglineStyle.Render(p.Tagline))
-	lines = append(lines, taglineLine)
+	// 1) Name and 2) tagline (slightly dimmer); the avatar joins them
+	// further down, once we know how much room is left for it
+	name := m.styles.name.Render(p.Name)
+	tagline := m.styles.tagline.Render(p.Tagline)
+	lines = append(lines, m.centerInContent(name), m.centerInContent(tagline))
 
 	// Blank line
 	lines = append(lines, "")
@@ -170,7 +231,19 @@ func (m model) viewOverview() string {
 		if b == "" {
 			continue
 		}
-		lines = append(lines, "• "+b)
+		lines = append(lines, m.glyphs.bullet+" "+b)
+	}
+
+	// The avatar goes beside the name and tagline, in the rows the rest
+	// leaves free, when the whole card fits in the window
+	if m.width == 0 || m.width >= appWidth+2 {
+		rest := lipgloss.Height(m.styles.wrap.Render(strings.Join(lines[2:], "\n")))
+		cols := appWidth - 4 - max(lipgloss.Width(name), lipgloss.Width(tagline)) - avatarGap
+		if art := m.viewAvatar(cols, appHeight-5-rest); art != "" {
+			text := lipgloss.JoinVertical(lipgloss.Left, name, tagline)
+			header := lipgloss.JoinHorizontal(lipgloss.Center, art, strings.Repeat(" ", avatarGap), text)
+			lines = append([]string{m.centerInContent(header)}, lines[2:]...)
+		}
 	}
 
 	// 5) Social links line at the bottom of the content box
@@ -190,7 +263,7 @@ func (m model) viewOverview() string {
 	// if len(socialParts) > 0 {
 	// 	lines = append(lines, "")
 	// 	socialLine := strings.Join(socialParts, "  ·  ")
-	// 	lines = append(lines, centerInContent(socialLine))
+	// 	lines = append(lines, m.centerInContent(socialLine))
 	// }
 
 	return strings.Join(lines, "\n")
@@ -214,8 +287,8 @@ func (m model) viewExperience() string {
 	var lines []string
 
 	// Title line: Company — Role
-	header := fmt.Sprintf("%s — %s", exp.Company, exp.Role)
-	header = lipgloss.NewStyle().Bold(true).Render(header)
+	header := fmt.Sprintf("%s %s %s", exp.Company, m.glyphs.dash, exp.Role)
+	header = m.styles.header.Render(header)
 	lines = append(lines, header)
 
 	// Period / location
@@ -227,9 +300,7 @@ func (m model) viewExperience() string {
 		metaParts = append(metaParts, exp.Location)
 	}
 	if len(metaParts) > 0 {
-		meta := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#AAAAAA")).
-			Render(strings.Join(metaParts, " · "))
+		meta := m.styles.meta.Render(strings.Join(metaParts, " "+m.glyphs.sep+" "))
 		lines = append(lines, meta)
 	}
 
@@ -245,7 +316,7 @@ func (m model) viewExperience() string {
 		if b == "" {
 			continue
 		}
-		lines = append(lines, "• "+b)
+		lines = append(lines, m.glyphs.bullet+" "+b)
 	}
 
 	// Stack line
@@ -284,7 +355,7 @@ func (m model) viewProjects() string {
 	}
 	proj := projs[idx]
 
-	header := lipgloss.NewStyle().Bold(true).Render(proj.Name)
+	header := m.styles.header.Render(proj.Name)
 	lines = append(lines, header)
 
 	lines = append(lines, "")
@@ -299,7 +370,7 @@ func (m model) viewProjects() string {
 		if b == "" {
 			continue
 		}
-		lines = append(lines, "• "+b)
+		lines = append(lines, m.glyphs.bullet+" "+b)
 	}
 
 	// Stack line
@@ -312,16 +383,15 @@ func (m model) viewProjects() string {
 
 	linksParts := []string{}
 
-	if proj.Links.Code != "" {
-		linksParts = append(linksParts, termLink("Code", proj.Links.Code))
-	}
-	if proj.Links.Demo != "" {
-		linksParts = append(linksParts, termLink("Demo", proj.Links.Demo))
+	for i, item := range m.copyItems() {
+		linksParts = append(linksParts, m.markSelected(i, item.view()))
 	}
 
 	if len(linksParts) > 0 {
 		lines = append(lines, "")
-		linkLine := strings.Join(linksParts, "  ·  ")
+		linkLine := strings.Join(linksParts, "  "+m.glyphs.sep+"  ")
+		// the footer is full on this tab, so the copy keys go here
+		linkLine += "    " + m.qrHint("tab: pick")
 		lines = append(lines, linkLine)
 	}
 
@@ -342,7 +412,7 @@ func (m model) viewProjects() string {
 // 	var lines []string
 
 // 	header := lipgloss.NewStyle().Bold(true).Render("Let's Work Together")
-// 	lines = append(lines, centerInContent(header))
+// 	lines = append(lines, m.centerInContent(header))
 
 // 	var socialParts []string
 
@@ -362,7 +432,7 @@ func (m model) viewProjects() string {
 // 	if len(socialParts) > 0 {
 // 		lines = append(lines, "")
 // 		socialLine := strings.Join(socialParts, "  ·  ")
-// 		lines = append(lines, centerInContent(socialLine))
+// 		lines = append(lines, m.centerInContent(socialLine))
 // 	}
 
 // 	return strings.Join(lines, "\n")
@@ -370,26 +440,31 @@ func (m model) viewProjects() string {
 // }
 
 func (m model) viewContact() string {
-	title := centerInContent(contactTitleStyle.Render("Let's Work Together"))
-
-	// Build individual items
-	items := []string{
-		termLink("GitHub", m.portfolio.Contact.GitHub),
-		termLink("LinkedIn", m.portfolio.Contact.LinkedIn),
-		termLink("Email", "mailto:"+m.portfolio.Contact.Email),
-		m.portfolio.Contact.Phone, // plain text, no link
+	if m.form.open {
+		return m.viewContactForm()
 	}
 
+	title := m.centerInContent(m.styles.contactTitle.Render("Let's Work Together"))
+
 	var lines []string
 	lines = append(lines, title) // title + blank line
-	lines = append(lines, centerInContent("I usually reply within 24–48 hours."), "")
+	lines = append(lines, m.centerInContent("I usually reply within 24"+m.glyphs.enDash+"48 hours."), "")
 
 	// Center each contact item on its own line
-	for _, item := range items {
-		if strings.TrimSpace(item) == "" {
-			continue
+	items := m.copyItems()
+	for i, item := range items {
+		lines = append(lines, m.centerInContent(m.markSelected(i, item.view())), "")
+	}
+	if len(items) > 0 {
+		lines = append(lines, m.centerInContent(m.qrHint("j/k: pick")))
+	}
+
+	if m.contact != nil {
+		hint := m.form.status
+		if hint == "" {
+			hint = m.styles.meta.Render("m: send me a message from here")
 		}
-		lines = append(lines, centerInContent(item), "")
+		lines = append(lines, m.centerInContent(hint))
 	}
 
 	return strings.Join(lines, "\n")